@@ -2,10 +2,11 @@ module github.com/justenstall/omap
 
 go 1.23
 
+require gopkg.in/yaml.v3 v3.0.1
+
 // Testing dependencies
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.11.1
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
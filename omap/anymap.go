@@ -0,0 +1,51 @@
+package omap
+
+import (
+	"cmp"
+	"iter"
+	"reflect"
+
+	"github.com/justenstall/omap/omap/internal/anymap"
+)
+
+// AnyMap returns an untyped view of m for the encodings in the
+// subpackages of omap. It cannot be called outside of this module.
+func (m *Map[K, V]) AnyMap(anymap.Token) anymap.Map {
+	return anyMap[K, V]{m}
+}
+
+// anyMap implements [anymap.Map] for a *Map.
+type anyMap[K cmp.Ordered, V any] struct {
+	m *Map[K, V]
+}
+
+func (a anyMap[K, V]) KeyType() reflect.Type {
+	return reflect.TypeFor[K]()
+}
+
+func (a anyMap[K, V]) ValueType() reflect.Type {
+	return reflect.TypeFor[V]()
+}
+
+func (a anyMap[K, V]) All() iter.Seq2[any, any] {
+	return func(yield func(key, value any) bool) {
+		for key, value := range a.m.All() {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+func (a anyMap[K, V]) Set(key, value any) {
+	// A nil value does not assert to an interface type
+	var v V
+	if value != nil {
+		v = value.(V)
+	}
+	a.m.Set(key.(K), v)
+}
+
+func (a anyMap[K, V]) Clear() {
+	a.m.Clear()
+}
@@ -0,0 +1,26 @@
+package omap
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+
+	"github.com/justenstall/omap/omap/internal/anymap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnyMap(t *testing.T) {
+	m := New(E("b", any(1)), E("a", any("x")))
+	a := m.AnyMap(anymap.Token{})
+	assert.Equal(t, reflect.TypeFor[string](), a.KeyType(), "KeyType()")
+	assert.Equal(t, reflect.TypeFor[any](), a.ValueType(), "ValueType()")
+	assert.Equal(t, map[any]any{"b": 1, "a": "x"}, maps.Collect(a.All()), "All()")
+
+	a.Set("c", nil)
+	assert.Equal(t, []Entry[string, any]{{"b", 1}, {"a", "x"}, {"c", nil}}, m.Entries(), "Entries() after Set()")
+
+	assert.Panics(t, func() { a.Set(1, nil) }, "Set() with the wrong key type")
+
+	a.Clear()
+	assert.Equal(t, 0, m.Len(), "Len() after Clear()")
+}
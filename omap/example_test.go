@@ -0,0 +1,18 @@
+package omap_test
+
+import (
+	"fmt"
+
+	"github.com/justenstall/omap/omap"
+)
+
+func ExampleE() {
+	m := omap.New(omap.E("b", 2), omap.E("a", 1), omap.E("c", 3))
+	for key, value := range m.All() {
+		fmt.Println(key, value)
+	}
+	// Output:
+	// b 2
+	// a 1
+	// c 3
+}
@@ -0,0 +1,30 @@
+// Package anymap gives the encodings in the subpackages of omap access
+// to ordered maps without knowing their type parameters.
+package anymap
+
+import (
+	"iter"
+	"reflect"
+)
+
+// Token is passed to the methods of package omap that return a [Map],
+// which keeps them from being called outside of this module.
+type Token struct {
+	_ struct{}
+}
+
+// Map is an untyped view of an ordered map.
+type Map interface {
+	// KeyType returns the type of the map's keys.
+	KeyType() reflect.Type
+	// ValueType returns the type of the map's values.
+	ValueType() reflect.Type
+	// All returns an iterator over the entries of the map in order.
+	All() iter.Seq2[any, any]
+	// Set sets the value for a key. The key and value must have the
+	// map's key and value types, except that a nil value sets the
+	// zero value.
+	Set(key, value any)
+	// Clear removes all entries from the map.
+	Clear()
+}
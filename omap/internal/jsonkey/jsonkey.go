@@ -0,0 +1,136 @@
+// Package jsonkey converts the keys of ordered maps to and from the
+// strings used as the keys of JSON objects. It is shared by the omap
+// package and the encodings in its subpackages.
+package jsonkey
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Marshal marshals a key as a JSON string. Keys that implement
+// [encoding.TextMarshaler] are marshaled as the text they produce.
+// Float keys that are NaN or infinite cannot be represented
+// in JSON and return an error. Negative zero is marshaled
+// as "-0", which parses back to negative zero.
+func Marshal(key any) ([]byte, error) {
+	// Use the key's own text encoding
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(text))
+	}
+	// Check for non-finite floats, including named float types
+	if v := reflect.ValueOf(key); v.CanFloat() {
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("unsupported key value: %v", f)
+		}
+	}
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyJSON) == 0 {
+		// Return empty JSON string
+		return []byte(`""`), nil
+	}
+	// Check first character of JSON representation
+	switch keyJSON[0] {
+	case '"':
+		// JSON value is a string, return as-is
+		return keyJSON, nil
+	case '[', '{':
+		// JSON value is an array or object, return error
+		return nil, fmt.Errorf("unsupported key type: %T", key)
+	default:
+		// JSON value is a number, boolean, or null
+		// Format the key as a string (JSON only supports string keys in mappings)
+		keyJSON, err = json.Marshal(string(keyJSON))
+		if err != nil {
+			return nil, fmt.Errorf("formatting as string: %w", err)
+		}
+		return keyJSON, nil
+	}
+}
+
+// Format formats a key as a string, as it would appear
+// unquoted in a JSON object.
+func Format(key any) (string, error) {
+	keyJSON, err := Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	var keyString string
+	if err := json.Unmarshal(keyJSON, &keyString); err != nil {
+		return "", err
+	}
+	return keyString, nil
+}
+
+// Parse parses a string into key, as formatted by [Format].
+// key must be a pointer to a type that implements
+// [encoding.TextUnmarshaler], or whose underlying type
+// satisfies cmp.Ordered.
+func Parse(keyString string, key any) error {
+	// Use the key's own text decoding
+	if tu, ok := key.(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(keyString))
+	}
+	// Handle all types in cmp.Ordered, as well as booleans
+	switch typedKey := any(key).(type) {
+	case *int, *int8, *int16, *int32, *int64,
+		*uint, *uint8, *uint16, *uint32, *uint64, *uintptr,
+		*float32, *float64, *bool:
+		return unmarshal(keyString, typedKey)
+	case *string:
+		// Store string and return
+		*typedKey = keyString
+		return nil
+	default:
+		// Fall back to using reflection to check the
+		// underlying type of the key.
+		v := reflect.ValueOf(key)
+		if v.Kind() != reflect.Pointer {
+			// Key must be a pointer
+			return fmt.Errorf("unsupported key type: %T", key)
+		}
+
+		// We know that key is a pointer, so call Elem()
+		// to get the element type.
+		switch v.Elem().Kind() {
+		// Numeric types
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64,
+			reflect.Bool:
+			return unmarshal(keyString, key)
+		// String types
+		case reflect.String:
+			v.Elem().SetString(keyString)
+			return nil
+		default:
+			return fmt.Errorf("unsupported key type: %T", key)
+		}
+	}
+}
+
+// unmarshal unmarshals a number or boolean key from its JSON form.
+// Unlike [json.Unmarshal], it rejects null, which would leave the key
+// unchanged, and surrounding whitespace, so that each key is only parsed
+// from the string it is marshaled as.
+func unmarshal(keyString string, key any) error {
+	if keyString == "null" {
+		return errors.New("null is not a valid key")
+	}
+	if strings.TrimSpace(keyString) != keyString {
+		return errors.New("surrounding whitespace in key")
+	}
+	return json.Unmarshal([]byte(keyString), key)
+}
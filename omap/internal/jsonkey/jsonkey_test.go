@@ -0,0 +1,202 @@
+package jsonkey
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     any
+		want    string
+		wantErr bool
+	}{
+		{name: "string", key: "007", want: `"007"`},
+		{name: "int", key: 100, want: `"100"`},
+		{name: "float64", key: 1.5, want: `"1.5"`},
+		{name: "bool", key: true, want: `"true"`},
+		{name: "bool wrapper", key: boolWrapper(false), want: `"false"`},
+		{name: "slice", key: []int{1}, wantErr: true},
+		{name: "negative zero", key: math.Copysign(0, -1), want: `"-0"`},
+		{name: "NaN", key: math.NaN(), wantErr: true},
+		{name: "positive infinity", key: math.Inf(1), wantErr: true},
+		{name: "negative infinity float32", key: float32(math.Inf(-1)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.key)
+			if tt.wantErr {
+				assert.Error(t, err, "Marshal() error")
+				return
+			}
+			assert.NoError(t, err, "Marshal() error")
+			assert.Equal(t, tt.want, string(got), "Marshal() output")
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyString string
+		key       any
+		wantKey   any
+		wantErr   bool
+	}{
+		{
+			name:      "int",
+			keyString: "100",
+			key:       new(int),
+			wantKey:   ptrTo(int(100)),
+			wantErr:   false,
+		},
+		{
+			name:      "int8",
+			keyString: "100",
+			key:       new(int8),
+			wantKey:   ptrTo(int8(100)),
+			wantErr:   false,
+		},
+		{
+			name:      "int8 overflow",
+			keyString: "100000000",
+			key:       new(int8),
+			wantKey:   ptrTo(int8(0)),
+			wantErr:   true,
+		},
+		{
+			name:      "float64",
+			keyString: "100.05",
+			key:       new(float64),
+			wantKey:   ptrTo(float64(100.05)),
+			wantErr:   false,
+		},
+		{
+			name:      "bool",
+			keyString: "true",
+			key:       new(bool),
+			wantKey:   ptrTo(true),
+			wantErr:   false,
+		},
+		{
+			name:      "bool invalid",
+			keyString: "1",
+			key:       new(bool),
+			wantKey:   ptrTo(false),
+			wantErr:   true,
+		},
+		{
+			name:      "bool wrapper",
+			keyString: "false",
+			key:       ptrTo(boolWrapper(true)),
+			wantKey:   ptrTo(boolWrapper(false)),
+			wantErr:   false,
+		},
+		{
+			name:      "int null",
+			keyString: "null",
+			key:       ptrTo(int(1)),
+			wantKey:   ptrTo(int(1)),
+			wantErr:   true,
+		},
+		{
+			name:      "int surrounding whitespace",
+			keyString: " 100\n",
+			key:       new(int),
+			wantKey:   new(int),
+			wantErr:   true,
+		},
+		{
+			name:      "bool null",
+			keyString: "null",
+			key:       ptrTo(true),
+			wantKey:   ptrTo(true),
+			wantErr:   true,
+		},
+		{
+			name:      "bool wrapper null",
+			keyString: "null",
+			key:       ptrTo(boolWrapper(true)),
+			wantKey:   ptrTo(boolWrapper(true)),
+			wantErr:   true,
+		},
+		{
+			name:      "float64 surrounding whitespace",
+			keyString: "\t1.5",
+			key:       new(float64),
+			wantKey:   new(float64),
+			wantErr:   true,
+		},
+		{
+			name:      "string null",
+			keyString: "null",
+			key:       new(string),
+			wantKey:   ptrTo("null"),
+			wantErr:   false,
+		},
+		{
+			name:      "string surrounding whitespace",
+			keyString: " a ",
+			key:       new(string),
+			wantKey:   ptrTo(" a "),
+			wantErr:   false,
+		},
+		{
+			name:      "string",
+			keyString: "[1,2,3]",
+			key:       new(string),
+			wantKey:   ptrTo("[1,2,3]"),
+			wantErr:   false,
+		},
+		{
+			name:      "string double pointer",
+			keyString: "[1,2,3]",
+			key:       new(*string),
+			wantKey:   new(*string),
+			wantErr:   true,
+		},
+		{
+			name:      "non pointer",
+			keyString: "100",
+			key:       int(0),
+			wantKey:   int(0),
+			wantErr:   true,
+		},
+		{
+			name:      "int wrapper",
+			keyString: "100",
+			key:       new(intWrapper),
+			wantKey:   ptrTo(intWrapper(100)),
+			wantErr:   false,
+		},
+		{
+			name:      "string wrapper",
+			keyString: "100",
+			key:       new(stringWrapper),
+			wantKey:   ptrTo(stringWrapper("100")),
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Parse(tt.keyString, tt.key)
+			if tt.wantErr {
+				assert.Error(t, err, "Parse() error")
+			} else {
+				assert.NoError(t, err, "Parse() error")
+			}
+			assert.Equal(t, tt.wantKey, tt.key, "Parse() output")
+		})
+	}
+}
+
+type intWrapper int
+type stringWrapper string
+type boolWrapper bool
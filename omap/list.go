@@ -0,0 +1,231 @@
+package omap
+
+import "slices"
+
+// list holds the entries of an ordered map along with their order.
+// It is shared by [Map] and [UnorderedKeyMap], which check for a
+// nil receiver before calling its methods.
+type list[K comparable, V any] struct {
+	entries map[K]*element[K, V]
+	// order holds the elements in insertion order.
+	// Deleted elements leave a nil tombstone behind,
+	// which is removed once tombstones make up
+	// more than half of the slice.
+	order []*element[K, V]
+	// deleted is the number of tombstones in order.
+	deleted int
+	// offset is the number of leading tombstones trimmed from
+	// order since it was last reindexed. An element's position
+	// in order is its index minus offset.
+	offset int
+	// max is the maximum number of entries, or 0 if unbounded.
+	// Once there are more entries, the first entry is evicted.
+	max int
+}
+
+// element is a key-value pair stored in a map,
+// along with its index in the map's order.
+// See [list.position].
+type element[K comparable, V any] struct {
+	key   K
+	value V
+	index int
+}
+
+// newList creates an empty list with the given capacity.
+func newList[K comparable, V any](capacity int) list[K, V] {
+	return list[K, V]{
+		entries: make(map[K]*element[K, V], capacity),
+		order:   make([]*element[K, V], 0, capacity),
+	}
+}
+
+// set sets the value for a key. If the key already exists,
+// its value will be overwritten and its position preserved.
+func (l *list[K, V]) set(key K, value V) {
+	// Check if key exists
+	if e, ok := l.entries[key]; ok {
+		// Update existing value and return
+		e.value = value
+		return
+	}
+
+	// Set value and add key to order
+	l.push(key, value)
+}
+
+// push adds a new key to the end of the order.
+// The key must not already exist in the map.
+func (l *list[K, V]) push(key K, value V) {
+	if l.entries == nil {
+		l.entries = map[K]*element[K, V]{}
+	}
+	e := &element[K, V]{key: key, value: value}
+	l.entries[key] = e
+	l.pushElement(e)
+	l.evict(e)
+}
+
+// pushElement adds an element to the end of the order.
+func (l *list[K, V]) pushElement(e *element[K, V]) {
+	e.index = l.offset + len(l.order)
+	l.order = append(l.order, e)
+}
+
+// insertElement inserts an element into the order at index i.
+// The element must not already be in the order.
+// This requires compacting the order, so it is O(n).
+func (l *list[K, V]) insertElement(i int, e *element[K, V]) {
+	l.compact()
+	l.order = slices.Insert(l.order, i, e)
+	l.reindex()
+}
+
+// evict removes entries from the front of the order until there are
+// no more than max entries. The element keep, which was just added or
+// moved, is skipped, so that an entry inserted at the front is kept.
+func (l *list[K, V]) evict(keep *element[K, V]) {
+	for l.max > 0 && len(l.entries) > l.max {
+		e := l.first()
+		if e == keep {
+			e = l.at(1)
+		}
+		l.remove(e)
+	}
+}
+
+// detach returns the element for a key with its value set to value,
+// removed from the order. If the key does not exist, a new element
+// is added to the map, but not to the order.
+func (l *list[K, V]) detach(key K, value V) *element[K, V] {
+	if e, ok := l.entries[key]; ok {
+		e.value = value
+		l.unlink(e)
+		return e
+	}
+	if l.entries == nil {
+		l.entries = map[K]*element[K, V]{}
+	}
+	e := &element[K, V]{key: key, value: value}
+	l.entries[key] = e
+	return e
+}
+
+// remove deletes an element from the map, leaving a tombstone in the order.
+func (l *list[K, V]) remove(e *element[K, V]) {
+	delete(l.entries, e.key)
+	l.unlink(e)
+}
+
+// unlink removes an element from the order, leaving a tombstone in its place.
+func (l *list[K, V]) unlink(e *element[K, V]) {
+	l.order[l.position(e)] = nil
+	l.deleted++
+
+	// Trim leading and trailing tombstones so both ends of the order stay dense
+	for len(l.order) > 0 && l.order[0] == nil {
+		l.order = l.order[1:]
+		l.offset++
+		l.deleted--
+	}
+	for len(l.order) > 0 && l.order[len(l.order)-1] == nil {
+		l.order = l.order[:len(l.order)-1]
+		l.deleted--
+	}
+
+	// Remove tombstones once they make up more than half of the order
+	if l.deleted > len(l.order)/2 {
+		l.compact()
+	}
+}
+
+// clear removes all entries, retaining the allocated capacity.
+func (l *list[K, V]) clear() {
+	clear(l.entries)
+	// Release the elements before truncating the order
+	clear(l.order)
+	l.order = l.order[:0]
+	l.deleted = 0
+	l.offset = 0
+}
+
+// compact removes all tombstones from the order.
+func (l *list[K, V]) compact() {
+	if l.deleted == 0 {
+		return
+	}
+	l.order = slices.DeleteFunc(l.order, func(e *element[K, V]) bool {
+		return e == nil
+	})
+	l.deleted = 0
+	l.reindex()
+}
+
+// position returns the position of an element in the order.
+func (l *list[K, V]) position(e *element[K, V]) int {
+	return e.index - l.offset
+}
+
+// at returns the element at index i in insertion order,
+// or nil if i is out of range.
+func (l *list[K, V]) at(i int) *element[K, V] {
+	if i < 0 || i >= len(l.entries) {
+		return nil
+	}
+	if l.deleted == 0 {
+		return l.order[i]
+	}
+	// Skip tombstones until reaching the element
+	for _, e := range l.order {
+		if e == nil {
+			continue
+		}
+		if i == 0 {
+			return e
+		}
+		i--
+	}
+	return nil
+}
+
+// indexOf returns the index of an element in insertion order.
+func (l *list[K, V]) indexOf(e *element[K, V]) int {
+	pos := l.position(e)
+	if l.deleted == 0 {
+		return pos
+	}
+	// Skip tombstones before the element
+	i := 0
+	for _, el := range l.order[:pos] {
+		if el != nil {
+			i++
+		}
+	}
+	return i
+}
+
+// first returns the first element in the order, or nil if the order is empty.
+func (l *list[K, V]) first() *element[K, V] {
+	if len(l.order) == 0 {
+		return nil
+	}
+	return l.order[0]
+}
+
+// last returns the last element in the order, or nil if the order is empty.
+func (l *list[K, V]) last() *element[K, V] {
+	if len(l.order) == 0 {
+		return nil
+	}
+	return l.order[len(l.order)-1]
+}
+
+// reindex updates the index of each element to match its position in the order.
+func (l *list[K, V]) reindex() {
+	l.offset = 0
+	for i, e := range l.order {
+		if e != nil {
+			e.index = i
+		}
+	}
+}
@@ -9,16 +9,21 @@ import (
 
 // Map is an ordered map.
 type Map[K cmp.Ordered, V any] struct {
-	entries map[K]V
-	order   []K
+	list[K, V]
 }
 
 // Entry represents an entry in a map.
-type Entry[K cmp.Ordered, V any] struct {
+type Entry[K comparable, V any] struct {
 	Key   K
 	Value V
 }
 
+// E returns an entry with the given key and value. It is shorthand for
+// constructing entries inline, as in New(E("a", 1), E("b", 2)).
+func E[K comparable, V any](key K, value V) Entry[K, V] {
+	return Entry[K, V]{Key: key, Value: value}
+}
+
 // New creates an ordered map from a list of entries.
 func New[K cmp.Ordered, V any](entries ...Entry[K, V]) *Map[K, V] {
 	m := NewWithCapacity[K, V](len(entries))
@@ -31,11 +36,27 @@ func New[K cmp.Ordered, V any](entries ...Entry[K, V]) *Map[K, V] {
 // NewWithCapacity creates an empty ordered map with the given capacity.
 func NewWithCapacity[K cmp.Ordered, V any](capacity int) *Map[K, V] {
 	return &Map[K, V]{
-		entries: make(map[K]V, capacity),
-		order:   make([]K, 0, capacity),
+		list: newList[K, V](capacity),
 	}
 }
 
+// NewBounded creates an empty ordered map that holds at most maxEntries
+// entries. Once adding a new key would exceed the limit, the first entry
+// in the order, which is the oldest unless the order has been changed,
+// is evicted. If the new key is inserted at the front, as by [Map.SetAt]
+// or [Map.InsertBefore], the entry after it is evicted instead, so the new
+// key is always kept. Updating the value of an existing key does not evict.
+// The limit is kept by [Map.Clone] and [Map.CloneFunc], but not by other
+// methods that return new maps. NewBounded panics if maxEntries is less than 1.
+func NewBounded[K cmp.Ordered, V any](maxEntries int) *Map[K, V] {
+	if maxEntries < 1 {
+		panic("omap: NewBounded called with non-positive maxEntries")
+	}
+	m := NewWithCapacity[K, V](maxEntries)
+	m.max = maxEntries
+	return m
+}
+
 // Collect collects key-value pairs from seq into a new ordered map and returns it.
 func Collect[K cmp.Ordered, V any](seq iter.Seq2[K, V]) *Map[K, V] {
 	m := New[K, V]()
@@ -43,13 +64,85 @@ func Collect[K cmp.Ordered, V any](seq iter.Seq2[K, V]) *Map[K, V] {
 	return m
 }
 
+// CollectEntries collects entries from seq into a new ordered map and returns it.
+// If several entries have the same key, the last value is kept at the position
+// of the first, as with [Map.Set].
+func CollectEntries[K cmp.Ordered, V any](seq iter.Seq[Entry[K, V]]) *Map[K, V] {
+	m := New[K, V]()
+	for entry := range seq {
+		m.Set(entry.Key, entry.Value)
+	}
+	return m
+}
+
+// CollectSlice creates an ordered map from the elements of s in order,
+// using f to derive the key and value of each element. If several elements
+// have the same key, the last value is kept at the position of the first.
+func CollectSlice[T any, K cmp.Ordered, V any](s []T, f func(T) (K, V)) *Map[K, V] {
+	m := NewWithCapacity[K, V](len(s))
+	for _, v := range s {
+		m.Set(f(v))
+	}
+	return m
+}
+
 // FromMap creates an ordered map from an existing map.
 // The existing entries are ordered by sorting the keys.
 func FromMap[K cmp.Ordered, V any](values map[K]V) *Map[K, V] {
-	return &Map[K, V]{
-		entries: values,
-		order:   slices.Sorted(maps.Keys(values)),
+	return FromMapFunc(values, cmp.Compare[K])
+}
+
+// FromMapFunc creates an ordered map from an existing map.
+// The existing entries are ordered by sorting the keys using the cmp
+// function, which must follow the semantics of [slices.SortFunc].
+func FromMapFunc[K cmp.Ordered, V any](values map[K]V, cmp func(a, b K) int) *Map[K, V] {
+	m := NewWithCapacity[K, V](len(values))
+	for _, key := range slices.SortedFunc(maps.Keys(values), cmp) {
+		m.push(key, values[key])
 	}
+	return m
+}
+
+// Grow grows the map's capacity, if necessary, to guarantee space for
+// another n entries. After Grow(n), at least n entries can be added to
+// the map without another allocation of the order. The underlying Go map
+// is only sized for the new entries if the map is empty, since growing
+// it would require copying the existing entries to a larger one.
+// If n is negative, Grow panics. If m is nil, Grow does nothing.
+func (m *Map[K, V]) Grow(n int) {
+	if n < 0 {
+		panic("omap: Grow called with negative count")
+	}
+	if m == nil || n == 0 {
+		return
+	}
+	if len(m.entries) == 0 {
+		m.entries = make(map[K]*element[K, V], n)
+	}
+	m.order = slices.Grow(m.order, n)
+}
+
+// Compact releases memory held by the map beyond what its current entries
+// need, by copying the entries into freshly allocated storage. Neither the
+// underlying Go map nor the order shrink when entries are deleted, so
+// Compact is useful for long-lived maps after many entries have been
+// deleted. The entries and their order are unchanged.
+func (m *Map[K, V]) Compact() {
+	if m == nil {
+		return
+	}
+	entries := make(map[K]*element[K, V], len(m.entries))
+	order := make([]*element[K, V], 0, len(m.entries))
+	for _, e := range m.order {
+		if e != nil {
+			entries[e.key] = e
+			order = append(order, e)
+		}
+	}
+	m.entries = entries
+	m.order = order
+	m.deleted = 0
+	m.reindex()
 }
 
 // Insert adds the key-value pairs from seq to m. If a key in seq already exists in m,
@@ -60,6 +153,86 @@ func (m *Map[K, V]) Insert(seq iter.Seq2[K, V]) {
 	}
 }
 
+// CollectInto adds the key-value pairs from seq to m, as with [Map.Insert],
+// and returns m so that several sequences can be collected into one map.
+// If the number of pairs in seq is known, it can be passed as sizeHint to
+// grow the map once beforehand, as with [Map.Grow]. Only the first
+// sizeHint is used. CollectInto panics if m is nil.
+func (m *Map[K, V]) CollectInto(seq iter.Seq2[K, V], sizeHint ...int) *Map[K, V] {
+	if m == nil {
+		panic("omap: CollectInto called on nil *Map")
+	}
+	if len(sizeHint) > 0 {
+		m.Grow(sizeHint[0])
+	}
+	for key, value := range seq {
+		m.set(key, value)
+	}
+	return m
+}
+
+// InsertMap adds the key-value pairs from values to m. If a key in values
+// already exists in m, its value will be overwritten and its insertion order
+// will be preserved. New keys are added in sorted order, as in [FromMap],
+// since iterating over a Go map visits keys in a random order that would
+// make the resulting order differ between runs. InsertMap panics if m is nil.
+func (m *Map[K, V]) InsertMap(values map[K]V) {
+	if m == nil {
+		panic("omap: InsertMap called on nil *Map")
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		m.Set(key, values[key])
+	}
+}
+
+// SetEntries sets the value for each entry in order, as with [Map.Set].
+// The map is grown once to fit all of the entries before they are set.
+// SetEntries panics if m is nil.
+func (m *Map[K, V]) SetEntries(entries ...Entry[K, V]) {
+	if m == nil {
+		panic("omap: SetEntries called on nil *Map")
+	}
+	m.Grow(len(entries))
+	for _, entry := range entries {
+		m.set(entry.Key, entry.Value)
+	}
+}
+
+// Merge copies all entries from other into m. New keys are added in
+// the insertion order of other, and existing keys have their value
+// overwritten and their insertion order preserved. A nil other is
+// treated as an empty map, leaving m unchanged. Merge panics if m is nil.
+func (m *Map[K, V]) Merge(other *Map[K, V]) {
+	if m == nil {
+		panic("omap: Merge called on nil *Map")
+	}
+	m.Insert(other.All())
+}
+
+// ApplyPatch applies changes such as those produced by [Diff] to m: the keys
+// in removed are deleted, the keys in changed are set to their values, and
+// the entries in added are set in their order. Keys already in m keep their
+// position, so changed values are overwritten in place and newly added keys
+// are appended to the back of the order. As a result, applying Diff(a, b)
+// to a yields a map with the entries of b, but only in the order of b if
+// the keys they share are in the same order and added keys come last.
+// Since Diff reports both the old and new values of changed keys, use
+// [MapValues] to select the new values. ApplyPatch panics if m is nil.
+func (m *Map[K, V]) ApplyPatch(added, removed, changed *Map[K, V]) {
+	if m == nil {
+		panic("omap: ApplyPatch called on nil *Map")
+	}
+	for key := range removed.Keys() {
+		m.Delete(key)
+	}
+	for key, value := range changed.All() {
+		m.set(key, value)
+	}
+	for key, value := range added.All() {
+		m.set(key, value)
+	}
+}
+
 // IsZero reports if map is empty.
 func (m *Map[K, V]) IsZero() bool {
 	return m == nil || len(m.entries) == 0
@@ -72,18 +245,19 @@ func (m *Map[K, V]) Get(key K) (value V, ok bool) {
 		var zero V
 		return zero, false
 	}
-	v, ok := m.entries[key]
-	return v, ok
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
 }
 
 // Value returns the value for a key. If the key does not exist,
 // value with be the zero value of its type.
 func (m *Map[K, V]) Value(key K) (value V) {
-	if m == nil || m.entries == nil {
-		var zero V
-		return zero
-	}
-	return m.entries[key]
+	value, _ = m.Get(key)
+	return value
 }
 
 // Has reports if the key is in the map.
@@ -95,28 +269,174 @@ func (m *Map[K, V]) Has(key K) (ok bool) {
 	return ok
 }
 
+// HasAll reports if all of the keys are in the map.
+// If no keys are given, HasAll returns true.
+func (m *Map[K, V]) HasAll(keys ...K) bool {
+	for _, key := range keys {
+		if !m.Has(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAny reports if any of the keys are in the map.
+// If no keys are given, HasAny returns false.
+func (m *Map[K, V]) HasAny(keys ...K) bool {
+	for _, key := range keys {
+		if m.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingKeys returns the keys that are not in the map,
+// in the order they are given. If all of the keys are
+// in the map, MissingKeys returns nil.
+func (m *Map[K, V]) MissingKeys(required ...K) []K {
+	var missing []K
+	for _, key := range required {
+		if !m.Has(key) {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// ContainsValue reports if any value in the map is equal to value,
+// using eq to compare values.
+func (m *Map[K, V]) ContainsValue(value V, eq func(a, b V) bool) bool {
+	_, ok := m.KeyOfValue(value, eq)
+	return ok
+}
+
+// KeyOfValue returns the first key in insertion order whose value is
+// equal to value, using eq to compare values. If no value matches,
+// ok will be false.
+func (m *Map[K, V]) KeyOfValue(value V, eq func(a, b V) bool) (key K, ok bool) {
+	for k, v := range m.All() {
+		if eq(v, value) {
+			return k, true
+		}
+	}
+	return key, false
+}
+
+// Any reports whether pred returns true for any entry in the map.
+// Entries are visited in insertion order, stopping at the first match.
+// Any returns false for an empty map.
+func (m *Map[K, V]) Any(pred func(key K, value V) bool) bool {
+	for key, value := range m.All() {
+		if pred(key, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllMatch reports whether pred returns true for every entry in the map.
+// Entries are visited in insertion order, stopping at the first non-match.
+// AllMatch returns true for an empty map.
+func (m *Map[K, V]) AllMatch(pred func(key K, value V) bool) bool {
+	for key, value := range m.All() {
+		if !pred(key, value) {
+			return false
+		}
+	}
+	return true
+}
+
 // Set sets the value for a key. If the key already exists in the map,
 // its value will be overwritten and its insertion order will be preserved.
+// Set panics if m is nil.
 func (m *Map[K, V]) Set(key K, value V) {
 	if m == nil {
-		m = NewWithCapacity[K, V](1)
+		panic("omap: Set called on nil *Map")
 	}
 
-	if m.entries == nil {
-		m.entries = map[K]V{}
-	}
+	m.set(key, value)
+}
 
-	// Check if key exists
-	_, ok := m.entries[key]
-	if ok {
-		// Update existing value and return
-		m.entries[key] = value
+// SetWith sets the value for a key. If the key already exists, its value
+// is set to the result of merge(old, value), where old is its current value,
+// and its position is preserved. Otherwise, the key is added with value.
+// SetWith panics if m is nil.
+func (m *Map[K, V]) SetWith(key K, value V, merge func(old, new V) V) {
+	if m == nil {
+		panic("omap: SetWith called on nil *Map")
+	}
+	if e, ok := m.entries[key]; ok {
+		e.value = merge(e.value, value)
 		return
 	}
+	m.push(key, value)
+}
 
-	// Set value and add key to order
-	m.entries[key] = value
-	m.order = append(m.order, key)
+// SetAndMove sets the value for a key and moves it to the back of the order.
+// Unlike [Map.Set], an existing key does not keep its position, which suits
+// maps ordered by most recent update, such as LRU caches.
+// SetAndMove panics if m is nil.
+func (m *Map[K, V]) SetAndMove(key K, value V) {
+	if m == nil {
+		panic("omap: SetAndMove called on nil *Map")
+	}
+	e := m.detach(key, value)
+	m.pushElement(e)
+	m.evict(e)
+}
+
+// GetOrSet returns the existing value for the key if present, with loaded
+// set to true. Otherwise, it sets the key to value and returns value,
+// with loaded set to false. GetOrSet panics if m is nil.
+func (m *Map[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	if m == nil {
+		panic("omap: GetOrSet called on nil *Map")
+	}
+	if e, ok := m.entries[key]; ok {
+		return e.value, true
+	}
+	m.push(key, value)
+	return value, false
+}
+
+// SetDefault sets the key to value only if the key is absent, and returns
+// the value for the key afterwards. It is like [Map.GetOrSet], without
+// reporting whether the value was set. SetDefault panics if m is nil.
+func (m *Map[K, V]) SetDefault(key K, value V) V {
+	if m == nil {
+		panic("omap: SetDefault called on nil *Map")
+	}
+	actual, _ := m.GetOrSet(key, value)
+	return actual
+}
+
+// GetOrCompute returns the existing value for the key if present.
+// Otherwise, it calls compute, sets the key to the result, and returns it.
+// compute is only called if the key is absent.
+// GetOrCompute panics if m is nil.
+func (m *Map[K, V]) GetOrCompute(key K, compute func() V) V {
+	if m == nil {
+		panic("omap: GetOrCompute called on nil *Map")
+	}
+	if e, ok := m.entries[key]; ok {
+		return e.value
+	}
+	value := compute()
+	m.Set(key, value)
+	return value
+}
+
+// Update sets the value for a key to the result of calling f with the
+// current value. If the key does not exist, f is called with the zero
+// value and ok set to false, and the key is added to the end of the order.
+// Existing keys keep their insertion order. Update panics if m is nil.
+func (m *Map[K, V]) Update(key K, f func(old V, ok bool) V) {
+	if m == nil {
+		panic("omap: Update called on nil *Map")
+	}
+	old, ok := m.Get(key)
+	m.Set(key, f(old, ok))
 }
 
 // Len returns the number of elements in the map.
@@ -127,47 +447,459 @@ func (m *Map[K, V]) Len() int {
 	return len(m.entries)
 }
 
+// Cap returns the capacity of the map's order, which is the number of
+// entries it can hold, including those deleted but not yet compacted,
+// before the order must be reallocated. See [Map.Grow] and [Map.Compact].
+// If m is nil, Cap returns 0.
+func (m *Map[K, V]) Cap() int {
+	if m == nil {
+		return 0
+	}
+	return cap(m.order)
+}
+
 // Delete removes a key from the map.
 func (m *Map[K, V]) Delete(key K) {
 	if m == nil {
 		return
 	}
-	// Delete from the map
-	delete(m.entries, key)
-	// Delete the key from the order
-	m.order = slices.DeleteFunc(m.order, func(v K) bool {
-		return v == key
+	if e, ok := m.entries[key]; ok {
+		m.remove(e)
+	}
+}
+
+// DeleteFunc removes all entries for which del returns true,
+// and returns the number of entries removed.
+func (m *Map[K, V]) DeleteFunc(del func(key K, value V) bool) int {
+	if m == nil {
+		return 0
+	}
+	n := 0
+	for i, e := range m.order {
+		if e != nil && del(e.key, e.value) {
+			delete(m.entries, e.key)
+			m.order[i] = nil
+			m.deleted++
+			n++
+		}
+	}
+	// Remove all tombstones at once
+	m.compact()
+	return n
+}
+
+// Retain removes all entries for which keep returns false,
+// and returns the number of entries removed. Unlike [Map.Filter],
+// Retain modifies m in place.
+func (m *Map[K, V]) Retain(keep func(key K, value V) bool) int {
+	return m.DeleteFunc(func(key K, value V) bool {
+		return !keep(key, value)
 	})
 }
 
-// SetOrder overwrites the order of the ordered map. The provided order
-// is sanitized by removing any keys not present in the map and
-// adding any additional keys in the map to the end of the order.
-func (m *Map[K, V]) SetOrder(order []K) {
-	// Sanitize input by removing any keys that
-	// do not match a value in the map
-	order = slices.DeleteFunc(order, func(key K) bool {
-		return m.Has(key)
+// Truncate removes all but the first n entries in insertion order.
+// If n is greater than or equal to the length of the map, Truncate
+// does nothing. If n is less than or equal to 0, all entries are removed.
+func (m *Map[K, V]) Truncate(n int) {
+	if m == nil || n >= len(m.entries) {
+		return
+	}
+	if n <= 0 {
+		m.clear()
+		return
+	}
+	m.compact()
+	for _, e := range m.order[n:] {
+		delete(m.entries, e.key)
+	}
+	// Release the elements before truncating the order
+	clear(m.order[n:])
+	m.order = m.order[:n]
+}
+
+// KeepLast removes all but the last n entries in insertion order,
+// which are the most recently inserted. If n is greater than or equal
+// to the length of the map, KeepLast does nothing. If n is less than
+// or equal to 0, all entries are removed.
+func (m *Map[K, V]) KeepLast(n int) {
+	if m == nil || n >= len(m.entries) {
+		return
+	}
+	if n <= 0 {
+		m.clear()
+		return
+	}
+	m.compact()
+	k := len(m.order) - n
+	for _, e := range m.order[:k] {
+		delete(m.entries, e.key)
+	}
+	// Release the elements before trimming the order
+	clear(m.order[:k])
+	m.order = m.order[k:]
+	m.offset += k
+}
+
+// At returns the entry at index i in insertion order.
+// If i is out of range, ok will be false.
+func (m *Map[K, V]) At(i int) (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.at(i)
+	if e == nil {
+		return entry, false
+	}
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// First returns the first entry in insertion order.
+// If the map is empty, ok will be false.
+func (m *Map[K, V]) First() (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.first()
+	if e == nil {
+		return entry, false
+	}
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// Last returns the last entry in insertion order.
+// If the map is empty, ok will be false.
+func (m *Map[K, V]) Last() (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.last()
+	if e == nil {
+		return entry, false
+	}
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// MinBy returns the entry with the smallest value, using cmp to compare
+// values. If several entries have the smallest value, the first one in
+// insertion order is returned. If the map is empty, ok will be false.
+func (m *Map[K, V]) MinBy(cmp func(a, b V) int) (entry Entry[K, V], ok bool) {
+	return m.extremeBy(func(a, b V) bool {
+		return cmp(a, b) < 0
 	})
+}
+
+// MaxBy returns the entry with the largest value, using cmp to compare
+// values. If several entries have the largest value, the first one in
+// insertion order is returned. If the map is empty, ok will be false.
+func (m *Map[K, V]) MaxBy(cmp func(a, b V) int) (entry Entry[K, V], ok bool) {
+	return m.extremeBy(func(a, b V) bool {
+		return cmp(a, b) > 0
+	})
+}
+
+// extremeBy returns the first entry whose value no other
+// value is better than, as reported by better.
+func (m *Map[K, V]) extremeBy(better func(a, b V) bool) (entry Entry[K, V], ok bool) {
+	for key, value := range m.All() {
+		if !ok || better(value, entry.Value) {
+			entry = Entry[K, V]{Key: key, Value: value}
+			ok = true
+		}
+	}
+	return entry, ok
+}
+
+// IndexOf returns the index of key in insertion order,
+// or -1 if the key is not in the map.
+func (m *Map[K, V]) IndexOf(key K) int {
+	if m == nil || m.entries == nil {
+		return -1
+	}
+	e, ok := m.entries[key]
+	if !ok {
+		return -1
+	}
+	return m.indexOf(e)
+}
+
+// Pop removes a key from the map and returns its value. If the key does not exist,
+// ok will be false and value with be the zero value of its type.
+func (m *Map[K, V]) Pop(key K) (value V, ok bool) {
+	if m == nil {
+		var zero V
+		return zero, false
+	}
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	m.remove(e)
+	return e.value, true
+}
+
+// PopFirst removes the first entry in the map and returns it.
+// If the map is empty, ok will be false.
+func (m *Map[K, V]) PopFirst() (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.first()
+	if e == nil {
+		return entry, false
+	}
+	m.remove(e)
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// PopLast removes the last entry in the map and returns it.
+// If the map is empty, ok will be false.
+func (m *Map[K, V]) PopLast() (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.last()
+	if e == nil {
+		return entry, false
+	}
+	m.remove(e)
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// Clear removes all entries from the map, retaining the allocated capacity
+// so the map can be reused.
+func (m *Map[K, V]) Clear() {
+	if m == nil {
+		return
+	}
+	m.clear()
+}
+
+// InsertBefore sets the value for a key and positions it immediately
+// before target in the order. If the key already exists in the map,
+// it is moved from its previous position. If the key is target, only
+// its value is updated. InsertBefore returns false without modifying
+// the map if target does not exist.
+func (m *Map[K, V]) InsertBefore(target, key K, value V) bool {
+	return m.insertAdjacent(target, key, value, false)
+}
+
+// InsertAfter sets the value for a key and positions it immediately
+// after target in the order. If the key already exists in the map,
+// it is moved from its previous position. If the key is target, only
+// its value is updated. InsertAfter returns false without modifying
+// the map if target does not exist.
+func (m *Map[K, V]) InsertAfter(target, key K, value V) bool {
+	return m.insertAdjacent(target, key, value, true)
+}
+
+// SetAt sets the value for a key and positions it at index i in the order.
+// If the key already exists in the map, it is moved from its previous
+// position, so i is relative to the order without the key. SetAt panics
+// if m is nil or if i is out of range, that is, not in [0, n] where n is
+// the number of other keys in the map.
+func (m *Map[K, V]) SetAt(i int, key K, value V) {
+	if m == nil {
+		panic("omap: SetAt called on nil *Map")
+	}
+	n := m.Len()
+	if m.Has(key) {
+		n--
+	}
+	if i < 0 || i > n {
+		panic("omap: SetAt index out of range")
+	}
+	e := m.detach(key, value)
+	m.insertElement(i, e)
+	m.evict(e)
+}
+
+// MoveToFront moves a key to the front of the order without changing its value.
+// If the key does not exist, MoveToFront does nothing.
+// Unlike [Map.MoveToBack], MoveToFront is O(n).
+func (m *Map[K, V]) MoveToFront(key K) {
+	if m == nil {
+		return
+	}
+	e, ok := m.entries[key]
+	if !ok || e == m.first() {
+		return
+	}
+	m.unlink(e)
+	m.insertElement(0, e)
+}
 
-	// Mark each key in order as visited
+// MoveToBack moves a key to the back of the order without changing its value.
+// If the key does not exist, MoveToBack does nothing.
+func (m *Map[K, V]) MoveToBack(key K) {
+	if m == nil {
+		return
+	}
+	e, ok := m.entries[key]
+	if !ok || e == m.last() {
+		return
+	}
+	m.unlink(e)
+	m.pushElement(e)
+}
+
+// Swap exchanges the positions of two keys in the order without
+// changing their values. Swap returns false without modifying
+// the map if either key does not exist.
+func (m *Map[K, V]) Swap(a, b K) bool {
+	if m == nil {
+		return false
+	}
+	ea, ok := m.entries[a]
+	if !ok {
+		return false
+	}
+	eb, ok := m.entries[b]
+	if !ok {
+		return false
+	}
+	m.order[m.position(ea)], m.order[m.position(eb)] = eb, ea
+	ea.index, eb.index = eb.index, ea.index
+	return true
+}
+
+// Rename changes the key of an entry from oldKey to newKey, keeping its
+// value and position in the order. Rename returns false without modifying
+// the map if oldKey does not exist or if newKey already exists.
+// Renaming a key to itself returns true if the key exists.
+func (m *Map[K, V]) Rename(oldKey, newKey K) bool {
+	if m == nil {
+		return false
+	}
+	e, ok := m.entries[oldKey]
+	if !ok {
+		return false
+	}
+	if oldKey == newKey {
+		return true
+	}
+	if _, ok := m.entries[newKey]; ok {
+		return false
+	}
+	delete(m.entries, oldKey)
+	e.key = newKey
+	m.entries[newKey] = e
+	return true
+}
+
+// SetOrder overwrites the order of the ordered map. The provided order
+// is sanitized by removing any keys not present in the map, keeping only
+// the first occurrence of duplicate keys, and adding any additional keys
+// in the map to the end of the order in sorted order.
+func (m *Map[K, V]) SetOrder(order []K) {
+	// Sanitize input by removing any keys that do not match
+	// a value in the map, as well as any duplicate keys
+	sanitized := make([]*element[K, V], 0, len(m.entries))
 	visited := map[K]struct{}{}
 	for _, key := range order {
+		e, ok := m.entries[key]
+		if !ok {
+			continue
+		}
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		// Add the element to the slice
+		sanitized = append(sanitized, e)
+		// Mark as visited
 		visited[key] = struct{}{}
 	}
 
 	// Add keys not in order to the end
 	for _, key := range slices.Sorted(maps.Keys(m.entries)) {
 		if _, ok := visited[key]; !ok {
-			// Add the key to the slice
-			order = append(order, key)
+			// Add the element to the slice
+			sanitized = append(sanitized, m.entries[key])
 			// Mark as visited
 			visited[key] = struct{}{}
 		}
 	}
 
 	// Store the slice
-	m.order = order
+	m.order = sanitized
+	m.deleted = 0
+	m.reindex()
+}
+
+// Equal reports whether m and other contain the same keys in the same
+// insertion order, with values compared using eq. A nil map is considered
+// equal to an empty map.
+func (m *Map[K, V]) Equal(other *Map[K, V], eq func(a, b V) bool) bool {
+	if m.Len() != other.Len() {
+		return false
+	}
+	if m.Len() == 0 {
+		return true
+	}
+	j := 0
+	for _, e := range m.order {
+		if e == nil {
+			continue
+		}
+		// Skip tombstones in other
+		for other.order[j] == nil {
+			j++
+		}
+		oe := other.order[j]
+		if e.key != oe.key || !eq(e.value, oe.value) {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// EqualUnordered reports whether m contains the same key-value pairs as
+// other, ignoring order, with values compared using eq. A nil map is
+// considered equal to an empty map.
+func (m *Map[K, V]) EqualUnordered(other map[K]V, eq func(a, b V) bool) bool {
+	if m.Len() != len(other) {
+		return false
+	}
+	for key, value := range m.All() {
+		otherValue, ok := other[key]
+		if !ok || !eq(value, otherValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// SortByKey sorts the order of m by key in ascending order.
+func (m *Map[K, V]) SortByKey() {
+	m.SortByKeyFunc(cmp.Compare[K])
+}
+
+// SortByKeyFunc sorts the order of m by key using the cmp function,
+// which must follow the semantics of [slices.SortFunc].
+func (m *Map[K, V]) SortByKeyFunc(cmp func(a, b K) int) {
+	if m == nil {
+		return
+	}
+	m.compact()
+	slices.SortFunc(m.order, func(a, b *element[K, V]) int {
+		return cmp(a.key, b.key)
+	})
+	m.reindex()
+}
+
+// SortByValueFunc sorts the order of m by value using the cmp function,
+// which must follow the semantics of [slices.SortStableFunc]. The sort is
+// stable, so entries with equal values keep their relative order.
+func (m *Map[K, V]) SortByValueFunc(cmp func(a, b V) int) {
+	if m == nil {
+		return
+	}
+	m.compact()
+	slices.SortStableFunc(m.order, func(a, b *element[K, V]) int {
+		return cmp(a.value, b.value)
+	})
+	m.reindex()
 }
 
 // Clone returns a copy of the ordered map. This is a shallow clone:
@@ -176,24 +908,195 @@ func (m *Map[K, V]) Clone() *Map[K, V] {
 	if m == nil {
 		return nil
 	}
-	return &Map[K, V]{
-		entries: maps.Clone(m.entries),
-		order:   slices.Clone(m.order),
+	cm := NewWithCapacity[K, V](m.Len())
+	cm.max = m.max
+	for key, value := range m.All() {
+		cm.push(key, value)
 	}
+	return cm
+}
+
+// Snapshot returns an independent copy of m for reading without a lock.
+// When one goroutine writes to m while others read it, call Snapshot while
+// holding the lock that guards m, then release the lock and read the
+// snapshot, which later changes to m do not affect. Like [Map.Clone], the
+// copy is shallow; use [Map.CloneFunc] to also copy values that hold
+// references. See [SyncMap] for a map that handles locking itself.
+func (m *Map[K, V]) Snapshot() *Map[K, V] {
+	return m.Clone()
+}
+
+// CloneFunc returns a copy of the ordered map with each value copied
+// by calling cloneValue, which can be used to deep copy values that
+// hold references, such as slices, maps, or pointers.
+// Keys are copied using ordinary assignment.
+func (m *Map[K, V]) CloneFunc(cloneValue func(V) V) *Map[K, V] {
+	if m == nil {
+		return nil
+	}
+	cm := NewWithCapacity[K, V](m.Len())
+	cm.max = m.max
+	for key, value := range m.All() {
+		cm.push(key, cloneValue(value))
+	}
+	return cm
+}
+
+// Filter returns a new ordered map containing the entries of m for which keep
+// returns true, in insertion order. m is not modified.
+func (m *Map[K, V]) Filter(keep func(key K, value V) bool) *Map[K, V] {
+	if m == nil {
+		return nil
+	}
+	fm := New[K, V]()
+	for key, value := range m.All() {
+		if keep(key, value) {
+			fm.push(key, value)
+		}
+	}
+	return fm
+}
+
+// Partition splits m into two new ordered maps: matched contains the entries
+// for which pred returns true, and rest contains the remaining entries.
+// Both maps preserve the relative insertion order of m, which is not modified.
+func (m *Map[K, V]) Partition(pred func(key K, value V) bool) (matched, rest *Map[K, V]) {
+	if m == nil {
+		return nil, nil
+	}
+	matched, rest = New[K, V](), New[K, V]()
+	for key, value := range m.All() {
+		if pred(key, value) {
+			matched.push(key, value)
+		} else {
+			rest.push(key, value)
+		}
+	}
+	return matched, rest
+}
+
+// Chunk splits m into new ordered maps of up to size entries each,
+// in insertion order. All maps except the last hold exactly size entries.
+// Chunk returns nil if m is empty, and panics if size is less than 1.
+func (m *Map[K, V]) Chunk(size int) []*Map[K, V] {
+	if size < 1 {
+		panic("omap: Chunk called with size less than 1")
+	}
+	if m.Len() == 0 {
+		return nil
+	}
+	chunks := make([]*Map[K, V], 0, (m.Len()+size-1)/size)
+	var chunk *Map[K, V]
+	for key, value := range m.All() {
+		if chunk.Len() == 0 || chunk.Len() == size {
+			chunk = NewWithCapacity[K, V](min(size, m.Len()-len(chunks)*size))
+			chunks = append(chunks, chunk)
+		}
+		chunk.push(key, value)
+	}
+	return chunks
+}
+
+// Slice returns a new ordered map containing the entries of m at indexes
+// [start, end) in insertion order. Unlike slicing a Go slice, out of range
+// indexes are clamped to [0, m.Len()] instead of panicking, and an empty
+// map is returned if start is not less than end. If m is nil, Slice
+// returns nil.
+func (m *Map[K, V]) Slice(start, end int) *Map[K, V] {
+	if m == nil {
+		return nil
+	}
+	start = max(start, 0)
+	end = min(end, m.Len())
+	if start >= end {
+		return New[K, V]()
+	}
+	sm := NewWithCapacity[K, V](end - start)
+	for i, entry := range m.Enumerate() {
+		if i >= end {
+			break
+		}
+		if i >= start {
+			sm.push(entry.Key, entry.Value)
+		}
+	}
+	return sm
 }
 
 // Backward returns a copy of the ordered map with the order reversed.
+// m is not modified, see [Map.Reverse] to reverse m in place.
 func (m *Map[K, V]) Backward() *Map[K, V] {
 	bm := m.Clone()
-	slices.Reverse(bm.order)
+	bm.Reverse()
 	return bm
 }
 
+// Reverse reverses the order of m in place.
+// Unlike [Map.Backward], Reverse modifies m instead of returning a copy.
+func (m *Map[K, V]) Reverse() {
+	if m == nil {
+		return
+	}
+	slices.Reverse(m.order)
+	m.reindex()
+}
+
+// ForEach calls fn for each entry in the map in insertion order,
+// stopping early if fn returns false.
+func (m *Map[K, V]) ForEach(fn func(key K, value V) bool) {
+	m.All()(fn)
+}
+
+// Entries returns a new slice of the entries in m in insertion order.
+// If m is nil, Entries returns nil.
+func (m *Map[K, V]) Entries() []Entry[K, V] {
+	if m == nil {
+		return nil
+	}
+	entries := make([]Entry[K, V], 0, m.Len())
+	for key, value := range m.All() {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+	}
+	return entries
+}
+
+// KeysSlice returns a new slice of the keys in m in insertion order.
+// If m is nil, KeysSlice returns nil.
+func (m *Map[K, V]) KeysSlice() []K {
+	if m == nil {
+		return nil
+	}
+	keys := make([]K, 0, m.Len())
+	for key := range m.Keys() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ValuesSlice returns a new slice of the values in m in insertion order.
+// If m is nil, ValuesSlice returns nil.
+func (m *Map[K, V]) ValuesSlice() []V {
+	if m == nil {
+		return nil
+	}
+	values := make([]V, 0, m.Len())
+	for value := range m.Values() {
+		values = append(values, value)
+	}
+	return values
+}
+
 // All returns an iterator over key-value pairs from m in insertion order.
 func (m *Map[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(key K, value V) bool) {
-		for key := range m.Keys() {
-			if !yield(key, m.Value(key)) {
+		if m == nil {
+			return
+		}
+		for _, e := range m.order {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key, e.value) {
 				return
 			}
 		}
@@ -203,8 +1106,85 @@ func (m *Map[K, V]) All() iter.Seq2[K, V] {
 // AllBackward returns an iterator over key-value pairs from m in reverse insertion order.
 func (m *Map[K, V]) AllBackward() iter.Seq2[K, V] {
 	return func(yield func(key K, value V) bool) {
-		for key := range m.KeysBackward() {
-			if !yield(key, m.Value(key)) {
+		if m == nil {
+			return
+		}
+		for _, e := range slices.Backward(m.order) {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// AllEntries returns an iterator over entries from m in insertion order.
+func (m *Map[K, V]) AllEntries() iter.Seq[Entry[K, V]] {
+	return func(yield func(entry Entry[K, V]) bool) {
+		for key, value := range m.All() {
+			if !yield(Entry[K, V]{Key: key, Value: value}) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over entries from m in insertion order,
+// along with their index in the order, starting from 0.
+func (m *Map[K, V]) Enumerate() iter.Seq2[int, Entry[K, V]] {
+	return func(yield func(i int, entry Entry[K, V]) bool) {
+		i := 0
+		for key, value := range m.All() {
+			if !yield(i, Entry[K, V]{Key: key, Value: value}) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Where returns an iterator over key-value pairs from m in insertion order,
+// yielding only entries for which pred returns true. Unlike [Map.Filter],
+// no new map is built: pred is called during each iteration, which
+// observes the current entries of m.
+func (m *Map[K, V]) Where(pred func(key K, value V) bool) iter.Seq2[K, V] {
+	return func(yield func(key K, value V) bool) {
+		for key, value := range m.All() {
+			if !pred(key, value) {
+				continue
+			}
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Between returns an iterator over key-value pairs from m in insertion
+// order, yielding only keys in the inclusive range [lo, hi] as compared
+// by [cmp.Compare]. If lo is greater than hi, nothing is yielded.
+func (m *Map[K, V]) Between(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(key K, value V) bool) {
+		for key, value := range m.All() {
+			if cmp.Compare(key, lo) < 0 || cmp.Compare(key, hi) > 0 {
+				continue
+			}
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// SortedKeys returns an iterator over keys in m in ascending order.
+// The keys are sorted when iteration begins, without changing the
+// insertion order of m, unlike [Map.SortByKey].
+func (m *Map[K, V]) SortedKeys() iter.Seq[K] {
+	return func(yield func(key K) bool) {
+		for _, key := range slices.Sorted(m.Keys()) {
+			if !yield(key) {
 				return
 			}
 		}
@@ -217,8 +1197,11 @@ func (m *Map[K, V]) Keys() iter.Seq[K] {
 		if m == nil {
 			return
 		}
-		for _, key := range m.order {
-			if !yield(key) {
+		for _, e := range m.order {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key) {
 				return
 			}
 		}
@@ -231,8 +1214,11 @@ func (m *Map[K, V]) KeysBackward() iter.Seq[K] {
 		if m == nil {
 			return
 		}
-		for _, key := range slices.Backward(m.order) {
-			if !yield(key) {
+		for _, e := range slices.Backward(m.order) {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key) {
 				return
 			}
 		}
@@ -242,8 +1228,14 @@ func (m *Map[K, V]) KeysBackward() iter.Seq[K] {
 // Values returns an iterator over values in m in insertion order.
 func (m *Map[K, V]) Values() iter.Seq[V] {
 	return func(yield func(value V) bool) {
-		for _, value := range m.All() {
-			if !yield(value) {
+		if m == nil {
+			return
+		}
+		for _, e := range m.order {
+			if e == nil {
+				continue
+			}
+			if !yield(e.value) {
 				return
 			}
 		}
@@ -253,10 +1245,36 @@ func (m *Map[K, V]) Values() iter.Seq[V] {
 // ValuesBackward returns an iterator over values in m in reverse insertion order.
 func (m *Map[K, V]) ValuesBackward() iter.Seq[V] {
 	return func(yield func(value V) bool) {
-		for _, value := range m.AllBackward() {
-			if !yield(value) {
+		if m == nil {
+			return
+		}
+		for _, e := range slices.Backward(m.order) {
+			if e == nil {
+				continue
+			}
+			if !yield(e.value) {
 				return
 			}
 		}
 	}
 }
+
+// insertAdjacent sets the value for a key and positions it
+// immediately before or after target in the order.
+func (m *Map[K, V]) insertAdjacent(target, key K, value V, after bool) bool {
+	if m == nil || !m.Has(target) {
+		return false
+	}
+	if key == target {
+		m.entries[key].value = value
+		return true
+	}
+	e := m.detach(key, value)
+	i := m.IndexOf(target)
+	if after {
+		i++
+	}
+	m.insertElement(i, e)
+	m.evict(e)
+	return true
+}
@@ -0,0 +1,1932 @@
+package omap
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetOrder(t *testing.T) {
+	t.Run("custom order", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+		m.SetOrder([]string{"b", "a", "c"})
+		assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()), "Keys() after SetOrder()")
+		assert.Equal(t, []int{2, 1, 3}, slices.Collect(m.Values()), "Values() after SetOrder()")
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+		m.SetOrder([]string{"c", "a", "c", "a"})
+		assert.Equal(t, []string{"c", "a", "b"}, slices.Collect(m.Keys()), "Keys() after SetOrder()")
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+		order := []string{"x", "c", "y"}
+		m.SetOrder(order)
+		assert.Equal(t, []string{"c", "a", "b"}, slices.Collect(m.Keys()), "Keys() after SetOrder()")
+		assert.Equal(t, []string{"x", "c", "y"}, order, "SetOrder() input")
+	})
+}
+
+func TestSet(t *testing.T) {
+	t.Run("nil map", func(t *testing.T) {
+		var m *Map[string, int]
+		assert.PanicsWithValue(t, "omap: Set called on nil *Map", func() {
+			m.Set("a", 1)
+		}, "Set() on nil map")
+	})
+
+	t.Run("zero map", func(t *testing.T) {
+		m := &Map[string, int]{}
+		m.Set("a", 1)
+		m.Set("b", 2)
+		m.Set("a", 3)
+		assert.Equal(t, []string{"a", "b"}, slices.Collect(m.Keys()), "Keys() after Set()")
+		assert.Equal(t, []int{3, 2}, slices.Collect(m.Values()), "Values() after Set()")
+	})
+}
+
+func TestSetWith(t *testing.T) {
+	sum := func(old, new int) int {
+		return old + new
+	}
+	m := New[string, int]()
+	for _, word := range []string{"b", "a", "b", "c", "b", "a"} {
+		m.SetWith(word, 1, sum)
+	}
+	assert.Equal(t, []Entry[string, int]{{"b", 3}, {"a", 2}, {"c", 1}}, m.Entries(), "Entries() after SetWith()")
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: SetWith called on nil *Map", func() {
+		nilMap.SetWith("a", 1, sum)
+	}, "SetWith() on nil map")
+}
+
+func TestSetAndMove(t *testing.T) {
+	entries := []Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}
+
+	set := New(entries...)
+	set.Set("a", 10)
+	set.Set("d", 4)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, set.KeysSlice(), "KeysSlice() after Set()")
+
+	moved := New(entries...)
+	moved.SetAndMove("a", 10)
+	moved.SetAndMove("d", 4)
+	moved.SetAndMove("d", 40)
+	assert.Equal(t, []string{"b", "c", "a", "d"}, moved.KeysSlice(), "KeysSlice() after SetAndMove()")
+	assert.Equal(t, []int{2, 3, 10, 40}, moved.ValuesSlice(), "ValuesSlice() after SetAndMove()")
+	assert.Equal(t, 2, moved.IndexOf("a"), "IndexOf() after SetAndMove()")
+
+	bounded := NewBounded[string, int](2)
+	bounded.SetAndMove("a", 1)
+	bounded.SetAndMove("b", 2)
+	bounded.SetAndMove("a", 10)
+	bounded.SetAndMove("c", 3)
+	assert.Equal(t, []string{"a", "c"}, bounded.KeysSlice(), "KeysSlice() after SetAndMove() on bounded map")
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: SetAndMove called on nil *Map", func() {
+		nilMap.SetAndMove("a", 1)
+	}, "SetAndMove() on nil map")
+}
+
+func TestNewBounded(t *testing.T) {
+	t.Run("evicts oldest", func(t *testing.T) {
+		m := NewBounded[int, string](3)
+		for i := range 5 {
+			m.Set(i, strconv.Itoa(i))
+		}
+		assert.Equal(t, []int{2, 3, 4}, m.KeysSlice(), "KeysSlice() after Set()")
+		assert.False(t, m.Has(1), "Has() for evicted key")
+		assert.Equal(t, 3, m.Len(), "Len() after Set()")
+	})
+
+	t.Run("update does not evict", func(t *testing.T) {
+		m := NewBounded[string, int](2)
+		m.Set("a", 1)
+		m.Set("b", 2)
+		m.Set("a", 10)
+		assert.Equal(t, []Entry[string, int]{{"a", 10}, {"b", 2}}, m.Entries(), "Entries() after updating existing key")
+	})
+
+	t.Run("other insertions", func(t *testing.T) {
+		m := NewBounded[string, int](2)
+		m.Set("a", 1)
+		m.Set("b", 2)
+		m.GetOrSet("c", 3)
+		assert.Equal(t, []string{"b", "c"}, m.KeysSlice(), "KeysSlice() after GetOrSet()")
+		m.InsertBefore("c", "d", 4)
+		assert.Equal(t, []string{"d", "c"}, m.KeysSlice(), "KeysSlice() after InsertBefore()")
+
+		cm := m.Clone()
+		cm.Set("e", 5)
+		assert.Equal(t, []string{"c", "e"}, cm.KeysSlice(), "KeysSlice() after Set() on Clone()")
+	})
+
+	t.Run("positioned insertions", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			insert func(m *Map[string, int])
+			want   []string
+		}{
+			{name: "SetAt front", insert: func(m *Map[string, int]) { m.SetAt(0, "c", 3) }, want: []string{"c", "b"}},
+			{name: "SetAt middle", insert: func(m *Map[string, int]) { m.SetAt(1, "c", 3) }, want: []string{"c", "b"}},
+			{name: "SetAt back", insert: func(m *Map[string, int]) { m.SetAt(2, "c", 3) }, want: []string{"b", "c"}},
+			{name: "InsertBefore first", insert: func(m *Map[string, int]) { m.InsertBefore("a", "c", 3) }, want: []string{"c", "b"}},
+			{name: "InsertBefore last", insert: func(m *Map[string, int]) { m.InsertBefore("b", "c", 3) }, want: []string{"c", "b"}},
+			{name: "InsertAfter first", insert: func(m *Map[string, int]) { m.InsertAfter("a", "c", 3) }, want: []string{"c", "b"}},
+			{name: "InsertAfter last", insert: func(m *Map[string, int]) { m.InsertAfter("b", "c", 3) }, want: []string{"b", "c"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := NewBounded[string, int](2)
+				m.Set("a", 1)
+				m.Set("b", 2)
+				tt.insert(m)
+				assert.Equal(t, tt.want, m.KeysSlice(), "KeysSlice() after insertion")
+				assert.Equal(t, 3, m.Value("c"), "Value() of inserted key")
+			})
+		}
+
+		m := NewBounded[string, int](1)
+		m.Set("a", 1)
+		assert.True(t, m.InsertBefore("a", "b", 2), "InsertBefore() with one entry")
+		assert.Equal(t, []Entry[string, int]{{"b", 2}}, m.Entries(), "Entries() after InsertBefore() with one entry")
+	})
+
+	t.Run("invalid max", func(t *testing.T) {
+		assert.PanicsWithValue(t, "omap: NewBounded called with non-positive maxEntries", func() {
+			NewBounded[string, int](0)
+		}, "NewBounded(0)")
+	})
+}
+
+func TestSetEntries(t *testing.T) {
+	m := New(Entry[string, int]{"a", 1})
+	m.SetEntries([]Entry[string, int]{
+		{"c", 3},
+		{"a", 10},
+		{"b", 2},
+	}...)
+	assert.Equal(t, []string{"a", "c", "b"}, slices.Collect(m.Keys()), "Keys() after SetEntries()")
+	assert.Equal(t, []int{10, 3, 2}, slices.Collect(m.Values()), "Values() after SetEntries()")
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: SetEntries called on nil *Map", func() {
+		nilMap.SetEntries(Entry[string, int]{"a", 1})
+	}, "SetEntries() on nil map")
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("preserves order", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+			{"d", 4},
+			{"e", 5},
+		}...)
+		m.Delete("b")
+		m.Delete("x")
+		assert.Equal(t, []string{"a", "c", "d", "e"}, slices.Collect(m.Keys()), "Keys() after Delete()")
+		assert.Equal(t, []string{"e", "d", "c", "a"}, slices.Collect(m.KeysBackward()), "KeysBackward() after Delete()")
+		m.Delete("e")
+		m.Delete("a")
+		assert.Equal(t, []string{"c", "d"}, slices.Collect(m.Keys()), "Keys() after Delete()")
+		assert.Equal(t, 2, m.Len(), "Len() after Delete()")
+		assert.False(t, m.Has("a"), "Has() after Delete()")
+	})
+
+	t.Run("reinsert", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+		m.Delete("a")
+		m.Set("a", 4)
+		assert.Equal(t, []string{"b", "c", "a"}, slices.Collect(m.Keys()), "Keys() after Delete() and Set()")
+		assert.Equal(t, []int{2, 3, 4}, slices.Collect(m.Values()), "Values() after Delete() and Set()")
+	})
+
+	t.Run("many", func(t *testing.T) {
+		m := NewWithCapacity[int, int](100)
+		for i := range 100 {
+			m.Set(i, i)
+		}
+		// Delete every key except multiples of 10, in a scattered order
+		for i := range 100 {
+			key := (i * 37) % 100
+			if key%10 != 0 {
+				m.Delete(key)
+			}
+		}
+		assert.Equal(t, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}, slices.Collect(m.Keys()), "Keys() after Delete()")
+		assert.Equal(t, 10, m.Len(), "Len() after Delete()")
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		var m *Map[string, int]
+		m.Delete("a")
+		assert.Equal(t, 0, m.Len(), "Len() after Delete()")
+	})
+}
+
+func BenchmarkDelete(b *testing.B) {
+	const n = 100_000
+	b.Run("sequential", func(b *testing.B) {
+		for range b.N {
+			b.StopTimer()
+			m := NewWithCapacity[int, int](n)
+			for i := range n {
+				m.Set(i, i)
+			}
+			b.StartTimer()
+			for i := range n {
+				m.Delete(i)
+			}
+		}
+	})
+	b.Run("reverse", func(b *testing.B) {
+		for range b.N {
+			b.StopTimer()
+			m := NewWithCapacity[int, int](n)
+			for i := range n {
+				m.Set(i, i)
+			}
+			b.StartTimer()
+			for i := n - 1; i >= 0; i-- {
+				m.Delete(i)
+			}
+		}
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{name: "negative", n: -1, want: []string{}},
+		{name: "zero", n: 0, want: []string{}},
+		{name: "middle", n: 2, want: []string{"a", "c"}},
+		{name: "length", n: 4, want: []string{"a", "c", "d", "e"}},
+		{name: "over length", n: 10, want: []string{"a", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New([]Entry[string, int]{
+				{"a", 1},
+				{"b", 2},
+				{"c", 3},
+				{"d", 4},
+				{"e", 5},
+			}...)
+			m.Delete("b")
+			m.Truncate(tt.n)
+			assert.Equal(t, tt.want, m.KeysSlice(), "KeysSlice() after Truncate()")
+			assert.Equal(t, len(tt.want), m.Len(), "Len() after Truncate()")
+			assert.False(t, m.Has("e") && len(tt.want) < 4, "Has() for removed key after Truncate()")
+
+			m.Set("f", 6)
+			assert.Equal(t, len(tt.want), m.IndexOf("f"), "IndexOf() for key set after Truncate()")
+		})
+	}
+}
+
+func TestKeepLast(t *testing.T) {
+	t.Run("keep 3 of 10", func(t *testing.T) {
+		m := New[int, string]()
+		for i := range 10 {
+			m.Set(i, strconv.Itoa(i))
+		}
+		m.KeepLast(3)
+		assert.Equal(t, []int{7, 8, 9}, m.KeysSlice(), "KeysSlice() after KeepLast()")
+		assert.False(t, m.Has(6), "Has() for removed key after KeepLast()")
+		assert.Equal(t, 1, m.IndexOf(8), "IndexOf() after KeepLast()")
+
+		m.Set(10, "10")
+		m.Delete(8)
+		assert.Equal(t, []int{7, 9, 10}, m.KeysSlice(), "KeysSlice() after Set() and Delete()")
+		assert.Equal(t, 2, m.IndexOf(10), "IndexOf() after Set() and Delete()")
+	})
+
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{name: "negative", n: -1, want: []int{}},
+		{name: "zero", n: 0, want: []int{}},
+		{name: "length", n: 3, want: []int{1, 2, 3}},
+		{name: "over length", n: 10, want: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New([]Entry[int, string]{{1, "a"}, {2, "b"}, {3, "c"}}...)
+			m.KeepLast(tt.n)
+			assert.Equal(t, tt.want, m.KeysSlice(), "KeysSlice() after KeepLast()")
+		})
+	}
+}
+
+func TestHasAllAny(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}...)
+	tests := []struct {
+		name    string
+		keys    []string
+		wantAll bool
+		wantAny bool
+	}{
+		{name: "none", keys: nil, wantAll: true, wantAny: false},
+		{name: "subset", keys: []string{"c", "a"}, wantAll: true, wantAny: true},
+		{name: "all", keys: []string{"a", "b", "c"}, wantAll: true, wantAny: true},
+		{name: "some missing", keys: []string{"a", "x"}, wantAll: false, wantAny: true},
+		{name: "all missing", keys: []string{"x", "y"}, wantAll: false, wantAny: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantAll, m.HasAll(tt.keys...), "HasAll()")
+			assert.Equal(t, tt.wantAny, m.HasAny(tt.keys...), "HasAny()")
+		})
+	}
+
+	var nilMap *Map[string, int]
+	assert.False(t, nilMap.HasAll("a"), "HasAll() on nil map")
+	assert.False(t, nilMap.HasAny("a"), "HasAny() on nil map")
+}
+
+func TestMissingKeys(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"host", 1},
+		{"port", 2},
+	}...)
+	assert.Equal(t, []string{"user", "db"}, m.MissingKeys("user", "port", "db", "host"), "MissingKeys() with absent keys")
+	assert.Nil(t, m.MissingKeys("port", "host"), "MissingKeys() with present keys")
+	assert.Nil(t, m.MissingKeys(), "MissingKeys() with no keys")
+
+	var nilMap *Map[string, int]
+	assert.Equal(t, []string{"a"}, nilMap.MissingKeys("a"), "MissingKeys() on nil map")
+}
+
+func TestPop(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}...)
+
+	value, ok := m.Pop("b")
+	assert.True(t, ok, "Pop() ok")
+	assert.Equal(t, 2, value, "Pop() value")
+	assert.Equal(t, []string{"a", "c"}, slices.Collect(m.Keys()), "Keys() after Pop()")
+
+	value, ok = m.Pop("b")
+	assert.False(t, ok, "Pop() ok for missing key")
+	assert.Equal(t, 0, value, "Pop() value for missing key")
+
+	var nilMap *Map[string, int]
+	_, ok = nilMap.Pop("a")
+	assert.False(t, ok, "Pop() ok on nil map")
+}
+
+func TestPopFirstLast(t *testing.T) {
+	t.Run("queue", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+			{"d", 4},
+		}...)
+
+		entry, ok := m.PopFirst()
+		assert.True(t, ok, "PopFirst() ok")
+		assert.Equal(t, Entry[string, int]{"a", 1}, entry, "PopFirst() entry")
+
+		entry, ok = m.PopLast()
+		assert.True(t, ok, "PopLast() ok")
+		assert.Equal(t, Entry[string, int]{"d", 4}, entry, "PopLast() entry")
+
+		m.Set("e", 5)
+		assert.Equal(t, []string{"b", "c", "e"}, slices.Collect(m.Keys()), "Keys() after PopFirst() and PopLast()")
+
+		var got []string
+		for {
+			entry, ok := m.PopFirst()
+			if !ok {
+				break
+			}
+			got = append(got, entry.Key)
+		}
+		assert.Equal(t, []string{"b", "c", "e"}, got, "PopFirst() until empty")
+		assert.Equal(t, 0, m.Len(), "Len() after PopFirst() until empty")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		m := New[string, int]()
+		_, ok := m.PopFirst()
+		assert.False(t, ok, "PopFirst() ok on empty map")
+		_, ok = m.PopLast()
+		assert.False(t, ok, "PopLast() ok on empty map")
+
+		var nilMap *Map[string, int]
+		_, ok = nilMap.PopFirst()
+		assert.False(t, ok, "PopFirst() ok on nil map")
+		_, ok = nilMap.PopLast()
+		assert.False(t, ok, "PopLast() ok on nil map")
+	})
+
+	t.Run("interleaved", func(t *testing.T) {
+		m := New[int, int]()
+		want := []int{}
+		for i := range 50 {
+			m.Set(i, i)
+			want = append(want, i)
+			if i%3 == 0 {
+				m.PopFirst()
+				want = want[1:]
+			}
+			if i%7 == 0 && len(want) > 0 {
+				m.Delete(want[len(want)/2])
+				want = slices.Delete(want, len(want)/2, len(want)/2+1)
+			}
+		}
+		assert.Equal(t, want, slices.Collect(m.Keys()), "Keys() after interleaved operations")
+	})
+}
+
+func TestClear(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}...)
+	m.Delete("a")
+	m.Clear()
+	assert.Equal(t, 0, m.Len(), "Len() after Clear()")
+	assert.False(t, m.Has("b"), "Has() after Clear()")
+	assert.Empty(t, slices.Collect(m.Keys()), "Keys() after Clear()")
+
+	m.Set("c", 3)
+	m.Set("a", 1)
+	assert.Equal(t, []string{"c", "a"}, slices.Collect(m.Keys()), "Keys() after Clear() and Set()")
+
+	var nilMap *Map[string, int]
+	nilMap.Clear()
+	assert.Equal(t, 0, nilMap.Len(), "Len() after Clear() on nil map")
+}
+
+func TestAt(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+
+	for i, want := range []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}} {
+		entry, ok := m.At(i)
+		assert.True(t, ok, "At(%d) ok", i)
+		assert.Equal(t, want, entry, "At(%d) entry", i)
+	}
+
+	for _, i := range []int{-1, 4, 100} {
+		_, ok := m.At(i)
+		assert.False(t, ok, "At(%d) ok", i)
+	}
+
+	m.Delete("b")
+	entry, ok := m.At(1)
+	assert.True(t, ok, "At(1) ok after Delete()")
+	assert.Equal(t, Entry[string, int]{"c", 3}, entry, "At(1) entry after Delete()")
+	_, ok = m.At(3)
+	assert.False(t, ok, "At(3) ok after Delete()")
+
+	var nilMap *Map[string, int]
+	_, ok = nilMap.At(0)
+	assert.False(t, ok, "At(0) ok on nil map")
+}
+
+func TestIndexOf(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+	assert.Equal(t, 0, m.IndexOf("a"), "IndexOf(a)")
+	assert.Equal(t, 3, m.IndexOf("d"), "IndexOf(d)")
+	assert.Equal(t, -1, m.IndexOf("x"), "IndexOf(x)")
+
+	m.Delete("b")
+	assert.Equal(t, 1, m.IndexOf("c"), "IndexOf(c) after Delete()")
+	assert.Equal(t, 2, m.IndexOf("d"), "IndexOf(d) after Delete()")
+	assert.Equal(t, -1, m.IndexOf("b"), "IndexOf(b) after Delete()")
+
+	var nilMap *Map[string, int]
+	assert.Equal(t, -1, nilMap.IndexOf("a"), "IndexOf(a) on nil map")
+}
+
+func TestFirstLast(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}...)
+
+	entry, ok := m.First()
+	assert.True(t, ok, "First() ok")
+	assert.Equal(t, Entry[string, int]{"a", 1}, entry, "First() entry")
+	entry, ok = m.Last()
+	assert.True(t, ok, "Last() ok")
+	assert.Equal(t, Entry[string, int]{"c", 3}, entry, "Last() entry")
+
+	m.Delete("a")
+	m.Delete("c")
+	entry, _ = m.First()
+	assert.Equal(t, Entry[string, int]{"b", 2}, entry, "First() entry after Delete()")
+	entry, _ = m.Last()
+	assert.Equal(t, Entry[string, int]{"b", 2}, entry, "Last() entry after Delete()")
+
+	m.Delete("b")
+	_, ok = m.First()
+	assert.False(t, ok, "First() ok on empty map")
+	_, ok = m.Last()
+	assert.False(t, ok, "Last() ok on empty map")
+
+	var nilMap *Map[string, int]
+	_, ok = nilMap.First()
+	assert.False(t, ok, "First() ok on nil map")
+	_, ok = nilMap.Last()
+	assert.False(t, ok, "Last() ok on nil map")
+}
+
+func TestMoveToFront(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+	m.MoveToFront("c")
+	assert.Equal(t, []string{"c", "a", "b", "d"}, slices.Collect(m.Keys()), "Keys() after MoveToFront(c)")
+	m.MoveToFront("c")
+	assert.Equal(t, []string{"c", "a", "b", "d"}, slices.Collect(m.Keys()), "Keys() after MoveToFront(c) again")
+	m.MoveToFront("d")
+	assert.Equal(t, []string{"d", "c", "a", "b"}, slices.Collect(m.Keys()), "Keys() after MoveToFront(d)")
+	m.MoveToFront("x")
+	assert.Equal(t, []string{"d", "c", "a", "b"}, slices.Collect(m.Keys()), "Keys() after MoveToFront(x)")
+	assert.Equal(t, []int{4, 3, 1, 2}, slices.Collect(m.Values()), "Values() after MoveToFront()")
+	assert.Equal(t, 2, m.IndexOf("a"), "IndexOf(a) after MoveToFront()")
+
+	var nilMap *Map[string, int]
+	nilMap.MoveToFront("a")
+}
+
+func TestMoveToBack(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+	m.MoveToBack("b")
+	assert.Equal(t, []string{"a", "c", "d", "b"}, slices.Collect(m.Keys()), "Keys() after MoveToBack(b)")
+	m.MoveToBack("b")
+	assert.Equal(t, []string{"a", "c", "d", "b"}, slices.Collect(m.Keys()), "Keys() after MoveToBack(b) again")
+	m.MoveToBack("a")
+	assert.Equal(t, []string{"c", "d", "b", "a"}, slices.Collect(m.Keys()), "Keys() after MoveToBack(a)")
+	m.MoveToBack("x")
+	assert.Equal(t, []string{"c", "d", "b", "a"}, slices.Collect(m.Keys()), "Keys() after MoveToBack(x)")
+	assert.Equal(t, []int{3, 4, 2, 1}, slices.Collect(m.Values()), "Values() after MoveToBack()")
+
+	// Repeatedly promote keys as in an LRU cache
+	for range 10 {
+		for _, key := range []string{"d", "c", "b", "a"} {
+			m.MoveToBack(key)
+		}
+	}
+	assert.Equal(t, []string{"d", "c", "b", "a"}, slices.Collect(m.Keys()), "Keys() after repeated MoveToBack()")
+	assert.Equal(t, 3, m.IndexOf("a"), "IndexOf(a) after repeated MoveToBack()")
+
+	var nilMap *Map[string, int]
+	nilMap.MoveToBack("a")
+}
+
+func TestApplyPatch(t *testing.T) {
+	eq := func(x, y int) bool { return x == y }
+	patch := func(a, b *Map[string, int]) {
+		added, removed, changed := Diff(a, b, eq)
+		a.ApplyPatch(added, removed, MapValues(changed, func(key string, change struct{ Old, New int }) int {
+			return change.New
+		}))
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		a := New([]Entry[string, int]{
+			{"host", 1},
+			{"port", 2},
+			{"user", 3},
+			{"debug", 4},
+		}...)
+		b := New([]Entry[string, int]{
+			{"host", 1},
+			{"port", 20},
+			{"debug", 40},
+			{"tls", 10},
+			{"db", 50},
+		}...)
+		patch(a, b)
+		assert.True(t, a.Equal(b, eq), "Equal() after ApplyPatch()")
+		assert.Equal(t, b.Entries(), a.Entries(), "Entries() after ApplyPatch()")
+	})
+
+	t.Run("reordered", func(t *testing.T) {
+		a := New([]Entry[string, int]{{"a", 1}, {"b", 2}}...)
+		b := New([]Entry[string, int]{{"c", 3}, {"b", 20}, {"a", 1}}...)
+		patch(a, b)
+		assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 20}, {"c", 3}}, a.Entries(), "Entries() after ApplyPatch()")
+		assert.True(t, a.EqualUnordered(map[string]int{"a": 1, "b": 20, "c": 3}, eq), "EqualUnordered() after ApplyPatch()")
+	})
+
+	t.Run("nil patches", func(t *testing.T) {
+		a := New(Entry[string, int]{"a", 1})
+		a.ApplyPatch(nil, nil, nil)
+		assert.Equal(t, []Entry[string, int]{{"a", 1}}, a.Entries(), "Entries() after ApplyPatch() with nil maps")
+	})
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: ApplyPatch called on nil *Map", func() {
+		nilMap.ApplyPatch(nil, nil, nil)
+	}, "ApplyPatch() on nil map")
+}
+
+func TestInsertMap(t *testing.T) {
+	values := map[string]int{"e": 5, "b": 20, "d": 4, "c": 3}
+	for range 10 {
+		m := New([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...)
+		m.InsertMap(values)
+		assert.Equal(t, []string{"b", "a", "c", "d", "e"}, slices.Collect(m.Keys()), "Keys() after InsertMap()")
+		assert.Equal(t, []int{20, 1, 3, 4, 5}, slices.Collect(m.Values()), "Values() after InsertMap()")
+	}
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: InsertMap called on nil *Map", func() {
+		nilMap.InsertMap(nil)
+	}, "InsertMap() on nil map")
+}
+
+func TestCollectInto(t *testing.T) {
+	first := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+	}...)
+	second := New([]Entry[string, int]{
+		{"c", 3},
+		{"b", 20},
+	}...)
+	m := NewWithCapacity[string, int](0)
+	got := m.CollectInto(first.All(), first.Len()).CollectInto(second.All())
+	assert.Same(t, m, got, "CollectInto() return value")
+	assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()), "Keys() after CollectInto()")
+	assert.Equal(t, []int{20, 1, 3}, slices.Collect(m.Values()), "Values() after CollectInto()")
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: CollectInto called on nil *Map", func() {
+		nilMap.CollectInto(first.All())
+	}, "CollectInto() on nil map")
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("overlapping", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+		other := New([]Entry[string, int]{
+			{"d", 4},
+			{"b", 20},
+			{"e", 5},
+		}...)
+		m.Merge(other)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, slices.Collect(m.Keys()), "Keys() after Merge()")
+		assert.Equal(t, []int{1, 20, 3, 4, 5}, slices.Collect(m.Values()), "Values() after Merge()")
+		assert.Equal(t, []string{"d", "b", "e"}, slices.Collect(other.Keys()), "other Keys() after Merge()")
+	})
+
+	t.Run("disjoint", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...)
+		m.Merge(New([]Entry[string, int]{
+			{"d", 4},
+			{"c", 3},
+		}...))
+		assert.Equal(t, []string{"b", "a", "d", "c"}, slices.Collect(m.Keys()), "Keys() after Merge()")
+		assert.Equal(t, []int{2, 1, 4, 3}, slices.Collect(m.Values()), "Values() after Merge()")
+	})
+
+	t.Run("nil other", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+		}...)
+		m.Merge(nil)
+		assert.Equal(t, []string{"a"}, slices.Collect(m.Keys()), "Keys() after Merge(nil)")
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		var nilMap *Map[string, int]
+		assert.PanicsWithValue(t, "omap: Merge called on nil *Map", func() {
+			nilMap.Merge(nil)
+		}, "Merge() on nil map")
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	var mu sync.Mutex
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+	}...)
+
+	mu.Lock()
+	snapshot := m.Snapshot()
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		m.Set("c", 3)
+		m.Set("a", 10)
+		m.Delete("b")
+	}()
+	wg.Wait()
+
+	assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 2}}, snapshot.Entries(), "Entries() of snapshot after modifying map")
+	assert.Equal(t, []Entry[string, int]{{"a", 10}, {"c", 3}}, m.Entries(), "Entries() after modifying map")
+}
+
+func TestCloneFunc(t *testing.T) {
+	m := New([]Entry[string, []int]{
+		{"a", []int{1, 2}},
+		{"b", []int{3}},
+	}...)
+	cm := m.CloneFunc(slices.Clone[[]int])
+	cm.Value("a")[0] = 10
+	cm.Set("c", []int{4})
+	assert.Equal(t, []int{1, 2}, m.Value("a"), "original Value() after modifying clone")
+	assert.Equal(t, []int{10, 2}, cm.Value("a"), "clone Value() after modifying clone")
+	assert.Equal(t, []string{"a", "b"}, slices.Collect(m.Keys()), "original Keys() after modifying clone")
+
+	var nilMap *Map[string, []int]
+	assert.Nil(t, nilMap.CloneFunc(slices.Clone[[]int]), "CloneFunc() on nil map")
+}
+
+func TestFilter(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"d", 4},
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"f", 6},
+	}...)
+	even := m.Filter(func(_ string, value int) bool {
+		return value%2 == 0
+	})
+	assert.Equal(t, []string{"d", "b", "f"}, slices.Collect(even.Keys()), "Keys() after Filter()")
+	assert.Equal(t, []int{4, 2, 6}, slices.Collect(even.Values()), "Values() after Filter()")
+	assert.Equal(t, 5, m.Len(), "original Len() after Filter()")
+
+	var nilMap *Map[string, int]
+	assert.Nil(t, nilMap.Filter(func(string, int) bool { return true }), "Filter() on nil map")
+}
+
+func TestEqual(t *testing.T) {
+	eq := func(a, b []int) bool {
+		return slices.Equal(a, b)
+	}
+	m := New([]Entry[string, []int]{
+		{"a", []int{1}},
+		{"b", []int{2, 2}},
+		{"c", []int{3, 3, 3}},
+	}...)
+
+	tests := []struct {
+		name  string
+		other *Map[string, []int]
+		want  bool
+	}{
+		{
+			name:  "identical",
+			other: m.Clone(),
+			want:  true,
+		},
+		{
+			name: "different order",
+			other: New([]Entry[string, []int]{
+				{"b", []int{2, 2}},
+				{"a", []int{1}},
+				{"c", []int{3, 3, 3}},
+			}...),
+			want: false,
+		},
+		{
+			name: "different value",
+			other: New([]Entry[string, []int]{
+				{"a", []int{1}},
+				{"b", []int{2}},
+				{"c", []int{3, 3, 3}},
+			}...),
+			want: false,
+		},
+		{
+			name: "different length",
+			other: New([]Entry[string, []int]{
+				{"a", []int{1}},
+				{"b", []int{2, 2}},
+			}...),
+			want: false,
+		},
+		{
+			name:  "nil",
+			other: nil,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.Equal(tt.other, eq), "Equal()")
+			assert.Equal(t, tt.want, tt.other.Equal(m, eq), "Equal() reversed")
+		})
+	}
+
+	t.Run("tombstones", func(t *testing.T) {
+		other := New([]Entry[string, []int]{
+			{"x", nil},
+			{"a", []int{1}},
+			{"y", nil},
+			{"b", []int{2, 2}},
+			{"c", []int{3, 3, 3}},
+		}...)
+		other.Delete("x")
+		other.Delete("y")
+		assert.True(t, m.Equal(other, eq), "Equal() with deleted keys")
+	})
+
+	t.Run("nil and empty", func(t *testing.T) {
+		var nilMap *Map[string, []int]
+		assert.True(t, nilMap.Equal(nil, eq), "Equal() of nil maps")
+		assert.True(t, nilMap.Equal(New[string, []int](), eq), "Equal() of nil and empty maps")
+	})
+}
+
+func TestKeyOfValue(t *testing.T) {
+	eq := func(a, b []int) bool {
+		return slices.Equal(a, b)
+	}
+	m := New([]Entry[string, []int]{
+		{"c", []int{1}},
+		{"b", []int{2, 2}},
+		{"a", []int{1}},
+	}...)
+
+	key, ok := m.KeyOfValue([]int{1}, eq)
+	assert.True(t, ok, "KeyOfValue() ok")
+	assert.Equal(t, "c", key, "KeyOfValue() returns first match")
+	assert.True(t, m.ContainsValue([]int{2, 2}, eq), "ContainsValue() for present value")
+
+	key, ok = m.KeyOfValue([]int{3}, eq)
+	assert.False(t, ok, "KeyOfValue() ok for missing value")
+	assert.Equal(t, "", key, "KeyOfValue() key for missing value")
+	assert.False(t, m.ContainsValue([]int{3}, eq), "ContainsValue() for missing value")
+
+	var nilMap *Map[string, []int]
+	assert.False(t, nilMap.ContainsValue([]int{1}, eq), "ContainsValue() on nil map")
+}
+
+func TestReverse(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+	m.Delete("b")
+	want := slices.Collect(m.KeysBackward())
+	m.Reverse()
+	assert.Equal(t, want, slices.Collect(m.Keys()), "Keys() after Reverse()")
+	assert.Equal(t, []int{4, 3, 1}, slices.Collect(m.Values()), "Values() after Reverse()")
+	assert.Equal(t, 3, m.Value("c"), "Value() after Reverse()")
+	assert.Equal(t, 0, m.IndexOf("d"), "IndexOf() after Reverse()")
+
+	m.Set("e", 5)
+	assert.Equal(t, []string{"d", "c", "a", "e"}, slices.Collect(m.Keys()), "Keys() after Reverse() and Set()")
+
+	var nilMap *Map[string, int]
+	nilMap.Reverse()
+	assert.Nil(t, nilMap.Backward(), "Backward() on nil map")
+}
+
+func TestSortedKeys(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"b", 2},
+	}...)
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.SortedKeys()), "SortedKeys()")
+	assert.Equal(t, []string{"c", "a", "b"}, slices.Collect(m.Keys()), "Keys() after SortedKeys()")
+
+	var nilMap *Map[string, int]
+	assert.Empty(t, slices.Collect(nilMap.SortedKeys()), "SortedKeys() on nil map")
+}
+
+func TestSortByKey(t *testing.T) {
+	m := New([]Entry[int, string]{
+		{3, "c"},
+		{1, "a"},
+		{5, "e"},
+		{4, "d"},
+		{2, "b"},
+	}...)
+	m.Delete(5)
+	m.SortByKey()
+	assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(m.Keys()), "Keys() after SortByKey()")
+	assert.Equal(t, []string{"a", "b", "c", "d"}, slices.Collect(m.Values()), "Values() after SortByKey()")
+	assert.Equal(t, 2, m.IndexOf(3), "IndexOf() after SortByKey()")
+
+	m.SortByKeyFunc(func(a, b int) int {
+		return b - a
+	})
+	assert.Equal(t, []int{4, 3, 2, 1}, slices.Collect(m.Keys()), "Keys() after SortByKeyFunc()")
+
+	var nilMap *Map[int, string]
+	nilMap.SortByKey()
+}
+
+func TestSortByValueFunc(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"go", 3},
+		{"rust", 1},
+		{"zig", 5},
+		{"c", 3},
+		{"java", 2},
+	}...)
+	m.SortByValueFunc(func(a, b int) int {
+		return b - a
+	})
+	assert.Equal(t, []string{"zig", "go", "c", "java", "rust"}, slices.Collect(m.Keys()), "Keys() after SortByValueFunc()")
+	assert.Equal(t, []int{5, 3, 3, 2, 1}, slices.Collect(m.Values()), "Values() after SortByValueFunc()")
+
+	var nilMap *Map[string, int]
+	nilMap.SortByValueFunc(func(a, b int) int { return a - b })
+}
+
+func TestEntries(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+		{"c", 3},
+	}...)
+	entries := m.Entries()
+	assert.Equal(t, []Entry[string, int]{{"b", 2}, {"a", 1}, {"c", 3}}, entries, "Entries()")
+
+	entries[0].Value = 20
+	assert.Equal(t, 2, m.Value("b"), "Value() after modifying Entries() result")
+
+	assert.Equal(t, []Entry[string, int]{}, New[string, int]().Entries(), "Entries() on empty map")
+
+	var nilMap *Map[string, int]
+	assert.Nil(t, nilMap.Entries(), "Entries() on nil map")
+}
+
+func TestAllEntries(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+		{"c", 3},
+	}...)
+	m.Delete("a")
+	assert.Equal(t, m.Entries(), slices.Collect(m.AllEntries()), "AllEntries() compared to Entries()")
+
+	for entry := range m.AllEntries() {
+		assert.Equal(t, Entry[string, int]{"b", 2}, entry, "AllEntries() stopped after first entry")
+		break
+	}
+
+	var nilMap *Map[string, int]
+	assert.Empty(t, slices.Collect(nilMap.AllEntries()), "AllEntries() on nil map")
+}
+
+func TestKeysSlice(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+		{"c", 3},
+	}...)
+	keys := m.KeysSlice()
+	assert.Equal(t, []string{"b", "a", "c"}, keys, "KeysSlice()")
+
+	keys[0] = "x"
+	slices.Reverse(keys)
+	assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()), "Keys() after modifying KeysSlice() result")
+
+	var nilMap *Map[string, int]
+	assert.Nil(t, nilMap.KeysSlice(), "KeysSlice() on nil map")
+}
+
+func TestValuesSlice(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+		{"c", 3},
+	}...)
+	values := m.ValuesSlice()
+	assert.Equal(t, []int{2, 1, 3}, values, "ValuesSlice()")
+
+	values[0] = 20
+	assert.Equal(t, []int{2, 1, 3}, slices.Collect(m.Values()), "Values() after modifying ValuesSlice() result")
+
+	var nilMap *Map[string, int]
+	assert.Nil(t, nilMap.ValuesSlice(), "ValuesSlice() on nil map")
+}
+
+func TestCollectEntries(t *testing.T) {
+	entries := []Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+		{"b", 20},
+		{"c", 3},
+	}
+	m := CollectEntries(slices.Values(entries))
+	assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()), "Keys() after CollectEntries()")
+	assert.Equal(t, []int{20, 1, 3}, slices.Collect(m.Values()), "Values() after CollectEntries()")
+}
+
+func TestCollectSlice(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := []user{
+		{ID: 3, Name: "carol"},
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 1, Name: "alicia"},
+	}
+	m := CollectSlice(users, func(u user) (int, string) {
+		return u.ID, u.Name
+	})
+	assert.Equal(t, []int{3, 1, 2}, slices.Collect(m.Keys()), "Keys() after CollectSlice()")
+	assert.Equal(t, []string{"carol", "alicia", "bob"}, slices.Collect(m.Values()), "Values() after CollectSlice()")
+}
+
+func TestFromMapFunc(t *testing.T) {
+	values := map[string]int{"b": 2, "d": 4, "a": 1, "c": 3}
+	m := FromMapFunc(values, func(a, b string) int {
+		return cmp.Compare(b, a)
+	})
+	assert.Equal(t, []string{"d", "c", "b", "a"}, slices.Collect(m.Keys()), "Keys() after FromMapFunc()")
+	assert.Equal(t, []int{4, 3, 2, 1}, slices.Collect(m.Values()), "Values() after FromMapFunc()")
+
+	m = FromMap(values)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, slices.Collect(m.Keys()), "Keys() after FromMap()")
+}
+
+func TestGrow(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+	}...)
+	m.Grow(10)
+	assert.GreaterOrEqual(t, cap(m.order)-len(m.order), 10, "spare order capacity after Grow()")
+	assert.Equal(t, []string{"b", "a"}, slices.Collect(m.Keys()), "Keys() after Grow()")
+	assert.Equal(t, 1, m.Value("a"), "Value() after Grow()")
+
+	allocs := testing.AllocsPerRun(1, func() {
+		m := New[int, int]()
+		m.Grow(100)
+		for i := range 100 {
+			m.Set(i, i)
+		}
+	})
+	// One allocation each for the map, its order, and the
+	// grown map and order, plus one per element
+	assert.LessOrEqual(t, allocs, float64(100+10), "allocations when setting after Grow()")
+
+	// The order already has room, and the entries are not copied
+	allocs = testing.AllocsPerRun(10, func() {
+		m.Grow(1)
+	})
+	assert.Equal(t, float64(0), allocs, "allocations by Grow() with spare capacity")
+
+	assert.Panics(t, func() { m.Grow(-1) }, "Grow() with negative count")
+
+	var nilMap *Map[string, int]
+	nilMap.Grow(10)
+}
+
+func BenchmarkSet(b *testing.B) {
+	const n = 10_000
+	b.Run("without grow", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			m := New[int, int]()
+			for i := range n {
+				m.Set(i, i)
+			}
+		}
+	})
+	b.Run("with grow", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			m := New[int, int]()
+			m.Grow(n)
+			for i := range n {
+				m.Set(i, i)
+			}
+		}
+	})
+	// Each call grows a non-empty map, which must not copy its entries
+	b.Run("SetEntries per entry", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			m := New(E(-1, -1))
+			for i := range n {
+				m.SetEntries(E(i, i))
+			}
+		}
+	})
+	b.Run("CollectInto per entry", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			m := New(E(-1, -1))
+			for i := range n {
+				m.CollectInto(maps.All(map[int]int{i: i}), 1)
+			}
+		}
+	})
+}
+
+func TestGetOrSet(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+	}...)
+
+	actual, loaded := m.GetOrSet("a", 10)
+	assert.True(t, loaded, "GetOrSet() loaded for present key")
+	assert.Equal(t, 1, actual, "GetOrSet() actual for present key")
+	assert.Equal(t, 1, m.Value("a"), "Value() after GetOrSet() for present key")
+
+	actual, loaded = m.GetOrSet("c", 3)
+	assert.False(t, loaded, "GetOrSet() loaded for absent key")
+	assert.Equal(t, 3, actual, "GetOrSet() actual for absent key")
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()), "Keys() after GetOrSet()")
+	assert.Equal(t, 3, m.Len(), "Len() after GetOrSet()")
+
+	var nilMap *Map[string, int]
+	assert.Panics(t, func() { nilMap.GetOrSet("a", 1) }, "GetOrSet() on nil map")
+}
+
+func TestSetDefault(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+	}...)
+	assert.Equal(t, 1, m.SetDefault("a", 10), "SetDefault() for present key")
+	assert.Equal(t, 1, m.Value("a"), "Value() after SetDefault() for present key")
+	assert.Equal(t, 3, m.SetDefault("c", 3), "SetDefault() for absent key")
+	assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, m.Entries(), "Entries() after SetDefault()")
+
+	var nilMap *Map[string, int]
+	assert.PanicsWithValue(t, "omap: SetDefault called on nil *Map", func() {
+		nilMap.SetDefault("a", 1)
+	}, "SetDefault() on nil map")
+}
+
+func TestGetOrCompute(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+	}...)
+
+	calls := 0
+	compute := func() int {
+		calls++
+		return 3
+	}
+
+	assert.Equal(t, 1, m.GetOrCompute("a", compute), "GetOrCompute() for present key")
+	assert.Equal(t, 0, calls, "compute calls for present key")
+
+	assert.Equal(t, 3, m.GetOrCompute("c", compute), "GetOrCompute() for absent key")
+	assert.Equal(t, 1, calls, "compute calls for absent key")
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()), "Keys() after GetOrCompute()")
+
+	assert.Equal(t, 3, m.GetOrCompute("c", compute), "GetOrCompute() for computed key")
+	assert.Equal(t, 1, calls, "compute calls for computed key")
+
+	var nilMap *Map[string, int]
+	assert.Panics(t, func() { nilMap.GetOrCompute("a", compute) }, "GetOrCompute() on nil map")
+}
+
+func TestUpdate(t *testing.T) {
+	m := New[string, int]()
+	increment := func(v int, _ bool) int {
+		return v + 1
+	}
+	for _, word := range []string{"b", "a", "b", "c", "b", "a"} {
+		m.Update(word, increment)
+	}
+	assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()), "Keys() after Update()")
+	assert.Equal(t, []int{3, 2, 1}, slices.Collect(m.Values()), "Values() after Update()")
+
+	var gotOK []bool
+	m.Update("a", func(v int, ok bool) int {
+		gotOK = append(gotOK, ok)
+		return v
+	})
+	m.Update("d", func(v int, ok bool) int {
+		gotOK = append(gotOK, ok)
+		return v
+	})
+	assert.Equal(t, []bool{true, false}, gotOK, "Update() ok values")
+	assert.Equal(t, 0, m.Value("d"), "Value() after Update() for absent key")
+
+	var nilMap *Map[string, int]
+	assert.Panics(t, func() { nilMap.Update("a", increment) }, "Update() on nil map")
+}
+
+func TestInsertBefore(t *testing.T) {
+	newMap := func() *Map[string, int] {
+		return New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+	}
+	tests := []struct {
+		name     string
+		target   string
+		key      string
+		value    int
+		want     bool
+		wantKeys []string
+	}{
+		{name: "head", target: "a", key: "x", value: 10, want: true, wantKeys: []string{"x", "a", "b", "c"}},
+		{name: "middle", target: "c", key: "x", value: 10, want: true, wantKeys: []string{"a", "b", "x", "c"}},
+		{name: "existing key", target: "a", key: "c", value: 10, want: true, wantKeys: []string{"c", "a", "b"}},
+		{name: "target key", target: "b", key: "b", value: 10, want: true, wantKeys: []string{"a", "b", "c"}},
+		{name: "missing target", target: "z", key: "x", value: 10, want: false, wantKeys: []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMap()
+			assert.Equal(t, tt.want, m.InsertBefore(tt.target, tt.key, tt.value), "InsertBefore()")
+			assert.Equal(t, tt.wantKeys, slices.Collect(m.Keys()), "Keys() after InsertBefore()")
+			if tt.want {
+				assert.Equal(t, tt.value, m.Value(tt.key), "Value() after InsertBefore()")
+			} else {
+				assert.False(t, m.Has(tt.key), "Has() after InsertBefore()")
+			}
+		})
+	}
+}
+
+func TestInsertAfter(t *testing.T) {
+	newMap := func() *Map[string, int] {
+		return New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+	}
+	tests := []struct {
+		name     string
+		target   string
+		key      string
+		value    int
+		want     bool
+		wantKeys []string
+	}{
+		{name: "middle", target: "a", key: "x", value: 10, want: true, wantKeys: []string{"a", "x", "b", "c"}},
+		{name: "tail", target: "c", key: "x", value: 10, want: true, wantKeys: []string{"a", "b", "c", "x"}},
+		{name: "existing key", target: "c", key: "a", value: 10, want: true, wantKeys: []string{"b", "c", "a"}},
+		{name: "target key", target: "b", key: "b", value: 10, want: true, wantKeys: []string{"a", "b", "c"}},
+		{name: "missing target", target: "z", key: "x", value: 10, want: false, wantKeys: []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMap()
+			assert.Equal(t, tt.want, m.InsertAfter(tt.target, tt.key, tt.value), "InsertAfter()")
+			assert.Equal(t, tt.wantKeys, slices.Collect(m.Keys()), "Keys() after InsertAfter()")
+			if tt.want {
+				assert.Equal(t, tt.value, m.Value(tt.key), "Value() after InsertAfter()")
+			} else {
+				assert.False(t, m.Has(tt.key), "Has() after InsertAfter()")
+			}
+		})
+	}
+}
+
+func TestSetAt(t *testing.T) {
+	newMap := func() *Map[string, int] {
+		return New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+		}...)
+	}
+	tests := []struct {
+		name     string
+		index    int
+		key      string
+		wantKeys []string
+	}{
+		{name: "head", index: 0, key: "x", wantKeys: []string{"x", "a", "b", "c"}},
+		{name: "middle", index: 2, key: "x", wantKeys: []string{"a", "b", "x", "c"}},
+		{name: "tail", index: 3, key: "x", wantKeys: []string{"a", "b", "c", "x"}},
+		{name: "existing key to head", index: 0, key: "c", wantKeys: []string{"c", "a", "b"}},
+		{name: "existing key to tail", index: 2, key: "a", wantKeys: []string{"b", "c", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMap()
+			m.SetAt(tt.index, tt.key, 10)
+			assert.Equal(t, tt.wantKeys, slices.Collect(m.Keys()), "Keys() after SetAt()")
+			assert.Equal(t, 10, m.Value(tt.key), "Value() after SetAt()")
+			assert.Equal(t, tt.index, m.IndexOf(tt.key), "IndexOf() after SetAt()")
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		m := newMap()
+		assert.Panics(t, func() { m.SetAt(-1, "x", 10) }, "SetAt() with negative index")
+		assert.Panics(t, func() { m.SetAt(4, "x", 10) }, "SetAt() with index past end")
+		assert.Panics(t, func() { m.SetAt(3, "a", 10) }, "SetAt() with index past end for existing key")
+		assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()), "Keys() after SetAt() panics")
+	})
+
+	t.Run("after delete", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+			{"c", 3},
+			{"d", 4},
+		}...)
+		m.Delete("b")
+		m.SetAt(1, "x", 10)
+		assert.Equal(t, []string{"a", "x", "c", "d"}, slices.Collect(m.Keys()), "Keys() after Delete() and SetAt()")
+	})
+}
+
+func TestSwap(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+	assert.True(t, m.Swap("a", "d"), "Swap(a, d)")
+	assert.Equal(t, []string{"d", "b", "c", "a"}, slices.Collect(m.Keys()), "Keys() after Swap(a, d)")
+	assert.Equal(t, []int{4, 2, 3, 1}, slices.Collect(m.Values()), "Values() after Swap(a, d)")
+	assert.Equal(t, 3, m.IndexOf("a"), "IndexOf(a) after Swap(a, d)")
+
+	assert.True(t, m.Swap("b", "b"), "Swap(b, b)")
+	assert.Equal(t, []string{"d", "b", "c", "a"}, slices.Collect(m.Keys()), "Keys() after Swap(b, b)")
+
+	assert.False(t, m.Swap("b", "x"), "Swap(b, x)")
+	assert.False(t, m.Swap("x", "b"), "Swap(x, b)")
+	assert.Equal(t, []string{"d", "b", "c", "a"}, slices.Collect(m.Keys()), "Keys() after Swap() with missing key")
+
+	// Swapped elements must still be deleted from the right position
+	m.Delete("a")
+	assert.Equal(t, []string{"d", "b", "c"}, slices.Collect(m.Keys()), "Keys() after Swap() and Delete()")
+
+	var nilMap *Map[string, int]
+	assert.False(t, nilMap.Swap("a", "b"), "Swap() on nil map")
+}
+
+func TestRename(t *testing.T) {
+	t.Run("no collision", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"B", 2},
+			{"c", 3},
+		}...)
+		assert.True(t, m.Rename("B", "b"), "Rename(B, b)")
+		assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()), "Keys() after Rename()")
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(m.Values()), "Values() after Rename()")
+		assert.False(t, m.Has("B"), "Has(B) after Rename()")
+		assert.Equal(t, 2, m.Value("b"), "Value(b) after Rename()")
+
+		m.Delete("b")
+		assert.Equal(t, []string{"a", "c"}, slices.Collect(m.Keys()), "Keys() after Rename() and Delete()")
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"a", 1},
+			{"b", 2},
+		}...)
+		assert.False(t, m.Rename("a", "b"), "Rename(a, b)")
+		assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 2}}, m.Entries(), "Entries() after Rename() collision")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		m := New(Entry[string, int]{"a", 1})
+		assert.False(t, m.Rename("x", "y"), "Rename(x, y)")
+		assert.False(t, m.Has("y"), "Has(y) after Rename()")
+		assert.True(t, m.Rename("a", "a"), "Rename(a, a)")
+
+		var nilMap *Map[string, int]
+		assert.False(t, nilMap.Rename("a", "b"), "Rename() on nil map")
+	})
+}
+
+func TestPartition(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"d", 4},
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"e", 5},
+	}...)
+	even, odd := m.Partition(func(_ string, value int) bool {
+		return value%2 == 0
+	})
+	assert.Equal(t, []Entry[string, int]{{"d", 4}, {"b", 2}}, even.Entries(), "matched Entries()")
+	assert.Equal(t, []Entry[string, int]{{"a", 1}, {"c", 3}, {"e", 5}}, odd.Entries(), "rest Entries()")
+	assert.Equal(t, 5, m.Len(), "original Len() after Partition()")
+
+	none, all := m.Partition(func(string, int) bool { return false })
+	assert.Equal(t, 0, none.Len(), "matched Len() when nothing matches")
+	assert.Equal(t, m.Entries(), all.Entries(), "rest Entries() when nothing matches")
+
+	var nilMap *Map[string, int]
+	matched, rest := nilMap.Partition(func(string, int) bool { return true })
+	assert.Nil(t, matched, "matched on nil map")
+	assert.Nil(t, rest, "rest on nil map")
+}
+
+func TestChunk(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+		{"e", 5},
+		{"f", 6},
+	}...)
+
+	chunkKeys := func(chunks []*Map[string, int]) [][]string {
+		var keys [][]string
+		for _, chunk := range chunks {
+			keys = append(keys, slices.Collect(chunk.Keys()))
+		}
+		return keys
+	}
+
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"d", "e", "f"}}, chunkKeys(m.Chunk(3)), "Chunk(3)")
+	assert.Equal(t, [][]string{{"a", "b", "c", "d"}, {"e", "f"}}, chunkKeys(m.Chunk(4)), "Chunk(4)")
+	assert.Equal(t, [][]string{{"a", "b", "c", "d", "e", "f"}}, chunkKeys(m.Chunk(10)), "Chunk(10)")
+	assert.Equal(t, [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}, {"f"}}, chunkKeys(m.Chunk(1)), "Chunk(1)")
+	assert.Equal(t, []int{4, 5, 6}, slices.Collect(m.Chunk(3)[1].Values()), "Chunk(3) values")
+
+	assert.Panics(t, func() { m.Chunk(0) }, "Chunk(0)")
+	assert.Nil(t, New[string, int]().Chunk(2), "Chunk() on empty map")
+
+	var nilMap *Map[string, int]
+	assert.Nil(t, nilMap.Chunk(2), "Chunk() on nil map")
+}
+
+func TestMinMaxBy(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"x", 5},
+		{"b", 1},
+		{"y", 5},
+	}...)
+
+	entry, ok := m.MinBy(cmp.Compare[int])
+	assert.True(t, ok, "MinBy() ok")
+	assert.Equal(t, Entry[string, int]{"a", 1}, entry, "MinBy() returns first of ties")
+
+	entry, ok = m.MaxBy(cmp.Compare[int])
+	assert.True(t, ok, "MaxBy() ok")
+	assert.Equal(t, Entry[string, int]{"x", 5}, entry, "MaxBy() returns first of ties")
+
+	_, ok = New[string, int]().MinBy(cmp.Compare[int])
+	assert.False(t, ok, "MinBy() ok on empty map")
+
+	var nilMap *Map[string, int]
+	_, ok = nilMap.MaxBy(cmp.Compare[int])
+	assert.False(t, ok, "MaxBy() ok on nil map")
+}
+
+func TestAny(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}...)
+
+	var visited []string
+	got := m.Any(func(key string, value int) bool {
+		visited = append(visited, key)
+		return value%2 == 0
+	})
+	assert.True(t, got, "Any() with a match")
+	assert.Equal(t, []string{"a", "b"}, visited, "Any() stops at first match")
+
+	assert.False(t, m.Any(func(_ string, value int) bool { return value > 3 }), "Any() without a match")
+	assert.False(t, New[string, int]().Any(func(string, int) bool { return true }), "Any() on empty map")
+
+	var nilMap *Map[string, int]
+	assert.False(t, nilMap.Any(func(string, int) bool { return true }), "Any() on nil map")
+}
+
+func TestAllMatch(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}...)
+
+	var visited []string
+	got := m.AllMatch(func(key string, value int) bool {
+		visited = append(visited, key)
+		return value%2 == 1
+	})
+	assert.False(t, got, "AllMatch() with a non-match")
+	assert.Equal(t, []string{"a", "b"}, visited, "AllMatch() stops at first non-match")
+
+	assert.True(t, m.AllMatch(func(_ string, value int) bool { return value > 0 }), "AllMatch() with all matches")
+	assert.True(t, New[string, int]().AllMatch(func(string, int) bool { return false }), "AllMatch() on empty map")
+
+	var nilMap *Map[string, int]
+	assert.True(t, nilMap.AllMatch(func(string, int) bool { return false }), "AllMatch() on nil map")
+}
+
+func TestForEach(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"b", 2},
+		{"d", 4},
+	}...)
+
+	var visited []string
+	m.ForEach(func(key string, _ int) bool {
+		visited = append(visited, key)
+		return true
+	})
+	assert.Equal(t, []string{"c", "a", "b", "d"}, visited, "ForEach() visits all entries in order")
+
+	visited = nil
+	m.ForEach(func(key string, value int) bool {
+		visited = append(visited, key)
+		return value != 1
+	})
+	assert.Equal(t, []string{"c", "a"}, visited, "ForEach() stops when fn returns false")
+
+	var nilMap *Map[string, int]
+	nilMap.ForEach(func(string, int) bool {
+		t.Error("ForEach() on nil map called fn")
+		return true
+	})
+}
+
+func TestDeleteFunc(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"b", 2},
+		{"d", 4},
+		{"e", 5},
+	}...)
+	n := m.DeleteFunc(func(_ string, value int) bool {
+		return value%2 == 1
+	})
+	assert.Equal(t, 3, n, "DeleteFunc() count")
+	assert.Equal(t, []Entry[string, int]{{"b", 2}, {"d", 4}}, m.Entries(), "Entries() after DeleteFunc()")
+	assert.Equal(t, 1, m.IndexOf("d"), "IndexOf() after DeleteFunc()")
+
+	assert.Equal(t, 0, m.DeleteFunc(func(string, int) bool { return false }), "DeleteFunc() count without matches")
+
+	var nilMap *Map[string, int]
+	assert.Equal(t, 0, nilMap.DeleteFunc(func(string, int) bool { return true }), "DeleteFunc() count on nil map")
+}
+
+func TestRetain(t *testing.T) {
+	m := New([]Entry[int, string]{
+		{1, "one"},
+		{2, "two"},
+		{3, "three"},
+		{4, "four"},
+		{5, "five"},
+		{6, "six"},
+	}...)
+	n := m.Retain(func(_ int, value string) bool {
+		return len(value) == 3
+	})
+	assert.Equal(t, 3, n, "Retain() count")
+	assert.Equal(t, []Entry[int, string]{{1, "one"}, {2, "two"}, {6, "six"}}, m.Entries(), "Entries() after Retain()")
+
+	assert.Equal(t, 0, New[int, string]().Retain(func(int, string) bool { return false }), "Retain() count on empty map")
+
+	var nilMap *Map[int, string]
+	assert.Equal(t, 0, nilMap.Retain(func(int, string) bool { return false }), "Retain() count on nil map")
+}
+
+func TestCap(t *testing.T) {
+	m := New[int, int]()
+	assert.Equal(t, 0, m.Cap(), "Cap() on empty map")
+
+	for i := range 1000 {
+		m.Set(i, i)
+	}
+	grown := m.Cap()
+	assert.GreaterOrEqual(t, grown, 1000, "Cap() after Set()")
+
+	m.Grow(500)
+	assert.GreaterOrEqual(t, m.Cap(), 1500, "Cap() after Grow()")
+
+	for i := range 990 {
+		m.Delete(i)
+	}
+	m.Compact()
+	assert.Equal(t, 10, m.Cap(), "Cap() after Compact()")
+
+	var nilMap *Map[int, int]
+	assert.Equal(t, 0, nilMap.Cap(), "Cap() on nil map")
+}
+
+func TestCompact(t *testing.T) {
+	m := NewWithCapacity[int, int](1000)
+	for i := range 1000 {
+		m.Set(i, i*10)
+	}
+	m.DeleteFunc(func(key, _ int) bool {
+		return key%100 != 0
+	})
+	m.Delete(500)
+	assert.GreaterOrEqual(t, cap(m.order), 1000, "order capacity before Compact()")
+
+	want := m.Entries()
+	m.Compact()
+	assert.Equal(t, 9, cap(m.order), "order capacity after Compact()")
+	assert.Equal(t, want, m.Entries(), "Entries() after Compact()")
+	assert.Equal(t, 5, m.IndexOf(600), "IndexOf() after Compact()")
+
+	m.Set(1, 1)
+	m.Delete(0)
+	assert.Equal(t, []int{100, 200, 300, 400, 600, 700, 800, 900, 1}, slices.Collect(m.Keys()), "Keys() after Compact() and Set()")
+
+	var nilMap *Map[int, int]
+	nilMap.Compact()
+}
+
+func TestEqualUnordered(t *testing.T) {
+	eq := func(a, b int) bool {
+		return a == b
+	}
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"b", 2},
+	}...)
+
+	tests := []struct {
+		name  string
+		other map[string]int
+		want  bool
+	}{
+		{name: "matching", other: map[string]int{"a": 1, "b": 2, "c": 3}, want: true},
+		{name: "different value", other: map[string]int{"a": 1, "b": 20, "c": 3}, want: false},
+		{name: "different key", other: map[string]int{"a": 1, "b": 2, "d": 3}, want: false},
+		{name: "missing key", other: map[string]int{"a": 1, "b": 2}, want: false},
+		{name: "extra key", other: map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}, want: false},
+		{name: "nil", other: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.EqualUnordered(tt.other, eq), "EqualUnordered()")
+		})
+	}
+
+	t.Run("nil and empty", func(t *testing.T) {
+		var nilMap *Map[string, int]
+		assert.True(t, nilMap.EqualUnordered(nil, eq), "EqualUnordered() of nil maps")
+		assert.True(t, nilMap.EqualUnordered(map[string]int{}, eq), "EqualUnordered() of nil and empty maps")
+	})
+}
+
+func TestEnumerate(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"x", 0},
+		{"a", 1},
+		{"b", 2},
+	}...)
+	m.Delete("x")
+
+	type pair struct {
+		i   int
+		key string
+	}
+	var got []pair
+	for i, entry := range m.Enumerate() {
+		got = append(got, pair{i, entry.Key})
+		// Index should match positional access
+		at, _ := m.At(i)
+		assert.Equal(t, at, entry, "At(%d)", i)
+	}
+	assert.Equal(t, []pair{{0, "c"}, {1, "a"}, {2, "b"}}, got, "Enumerate()")
+
+	for i := range m.Enumerate() {
+		if i == 1 {
+			break
+		}
+	}
+
+	var nilMap *Map[string, int]
+	for range nilMap.Enumerate() {
+		t.Error("Enumerate() on nil map yielded an entry")
+	}
+}
+
+func TestWhere(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+	}...)
+	even := func(key string, value int) bool {
+		return value%2 == 0
+	}
+	seq := m.Where(even)
+	assert.Equal(t, m.Filter(even), Collect(seq), "Collect(Where()) compared to Filter()")
+
+	m.Set("e", 6)
+	m.Delete("b")
+	assert.Equal(t, []int{4, 6}, slices.Collect(Collect(seq).Values()), "Where() after modifying map")
+}
+
+func TestBetween(t *testing.T) {
+	m := New([]Entry[int, string]{
+		{30, "c"},
+		{10, "a"},
+		{50, "e"},
+		{20, "b"},
+		{40, "d"},
+	}...)
+
+	tests := []struct {
+		name     string
+		lo, hi   int
+		wantKeys []int
+	}{
+		{name: "inclusive bounds", lo: 20, hi: 40, wantKeys: []int{30, 20, 40}},
+		{name: "bounds between keys", lo: 15, hi: 35, wantKeys: []int{30, 20}},
+		{name: "single key", lo: 50, hi: 50, wantKeys: []int{50}},
+		{name: "all keys", lo: 0, hi: 100, wantKeys: []int{30, 10, 50, 20, 40}},
+		{name: "no keys", lo: 41, hi: 49, wantKeys: nil},
+		{name: "inverted bounds", lo: 40, hi: 20, wantKeys: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []int
+			for key, value := range m.Between(tt.lo, tt.hi) {
+				keys = append(keys, key)
+				assert.Equal(t, m.Value(key), value, "Between() value for %d", key)
+			}
+			assert.Equal(t, tt.wantKeys, keys, "Between(%d, %d) keys", tt.lo, tt.hi)
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+		{"d", 4},
+		{"e", 5},
+	}...)
+
+	tests := []struct {
+		name       string
+		start, end int
+		wantKeys   []string
+	}{
+		{name: "head", start: 0, end: 2, wantKeys: []string{"a", "b"}},
+		{name: "middle", start: 1, end: 4, wantKeys: []string{"b", "c", "d"}},
+		{name: "tail", start: 3, end: 5, wantKeys: []string{"d", "e"}},
+		{name: "all", start: 0, end: 5, wantKeys: []string{"a", "b", "c", "d", "e"}},
+		{name: "negative start", start: -3, end: 2, wantKeys: []string{"a", "b"}},
+		{name: "end past length", start: 3, end: 100, wantKeys: []string{"d", "e"}},
+		{name: "start past length", start: 10, end: 20, wantKeys: []string{}},
+		{name: "empty window", start: 2, end: 2, wantKeys: []string{}},
+		{name: "inverted window", start: 4, end: 1, wantKeys: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Slice(tt.start, tt.end)
+			assert.Equal(t, tt.wantKeys, got.KeysSlice(), "Slice(%d, %d) keys", tt.start, tt.end)
+		})
+	}
+
+	t.Run("independent", func(t *testing.T) {
+		s := m.Slice(1, 3)
+		s.Set("b", 20)
+		s.Delete("c")
+		assert.Equal(t, 2, m.Value("b"), "original Value() after modifying slice")
+		assert.True(t, m.Has("c"), "original Has() after modifying slice")
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		var nilMap *Map[string, int]
+		assert.Nil(t, nilMap.Slice(0, 1), "Slice() on nil map")
+	})
+}
+
+func BenchmarkValues(b *testing.B) {
+	const n = 100_000
+	m := NewWithCapacity[int, int](n)
+	for i := range n {
+		m.Set(i, i)
+	}
+	// Leave tombstones in the order
+	for i := 0; i < n; i += 10 {
+		m.Delete(i)
+	}
+	b.Run("forward", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for value := range m.Values() {
+				sum += value
+			}
+		}
+	})
+	b.Run("backward", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for value := range m.ValuesBackward() {
+				sum += value
+			}
+		}
+	})
+	b.Run("keys", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for key := range m.Keys() {
+				sum += key
+			}
+		}
+	})
+}
+
+func TestE(t *testing.T) {
+	m := New(E("b", 2), E("a", 1))
+	assert.Equal(t, New([]Entry[string, int]{{"b", 2}, {"a", 1}}...), m, "New() with E()")
+	assert.Equal(t, Entry[int, bool]{Key: 1, Value: true}, E(1, true), "E()")
+}
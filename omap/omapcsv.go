@@ -0,0 +1,65 @@
+package omap
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/justenstall/omap/omap/internal/jsonkey"
+)
+
+// WriteCSV writes the entries of m to w as CSV records with two fields,
+// the key and the value, in insertion order. Keys are formatted as they
+// would appear unquoted in a JSON object. Values are formatted by calling
+// format, or with [fmt.Sprint] if format is nil.
+func (m *Map[K, V]) WriteCSV(w io.Writer, format func(value V) string) error {
+	if format == nil {
+		format = func(value V) string {
+			return fmt.Sprint(value)
+		}
+	}
+	cw := csv.NewWriter(w)
+	for key, value := range m.All() {
+		keyString, err := jsonkey.Format(key)
+		if err != nil {
+			return fmt.Errorf("formatting key (type %T): %w", key, err)
+		}
+		if err := cw.Write([]string{keyString, format(value)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads CSV records with two fields, the key and the value, from r
+// into a new ordered map in the order they appear. Keys are parsed as they
+// would be from a JSON object, and values are parsed by calling parse.
+// If several records have the same key, the last value is kept at the
+// position of the first, as with [Map.Set].
+func ReadCSV[K cmp.Ordered, V any](r io.Reader, parse func(s string) (V, error)) (*Map[K, V], error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	m := New[K, V]()
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var key K
+		if err := jsonkey.Parse(record[0], &key); err != nil {
+			return nil, fmt.Errorf("parsing key %q as %T: %w", record[0], key, err)
+		}
+		value, err := parse(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("parsing value for key %q: %w", record[0], err)
+		}
+		m.Set(key, value)
+	}
+}
@@ -0,0 +1,65 @@
+package omap
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSV(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		m := New([]Entry[string, string]{
+			{"name", "omap"},
+			{"description", "ordered, generic \"map\""},
+			{"empty", ""},
+		}...)
+		var sb strings.Builder
+		assert.NoError(t, m.WriteCSV(&sb, nil), "WriteCSV() error")
+		assert.Equal(t, "name,omap\ndescription,\"ordered, generic \"\"map\"\"\"\nempty,\n", sb.String(), "WriteCSV() output")
+
+		got, err := ReadCSV[string](strings.NewReader(sb.String()), func(s string) (string, error) {
+			return s, nil
+		})
+		assert.NoError(t, err, "ReadCSV() error")
+		assert.Equal(t, m, got, "ReadCSV() output")
+	})
+
+	t.Run("formatted values", func(t *testing.T) {
+		m := New([]Entry[int, float64]{
+			{2, 0.5},
+			{1, 1.25},
+		}...)
+		var sb strings.Builder
+		err := m.WriteCSV(&sb, func(value float64) string {
+			return strconv.FormatFloat(value, 'f', 2, 64)
+		})
+		assert.NoError(t, err, "WriteCSV() error")
+		assert.Equal(t, "2,0.50\n1,1.25\n", sb.String(), "WriteCSV() output")
+
+		got, err := ReadCSV[int](strings.NewReader(sb.String()), func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		})
+		assert.NoError(t, err, "ReadCSV() error")
+		assert.Equal(t, m, got, "ReadCSV() output")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := []struct {
+			name string
+			data string
+		}{
+			{name: "wrong number of fields", data: "a,1\nb,2,3\n"},
+			{name: "unterminated quote", data: "a,\"1\n"},
+			{name: "invalid key", data: "x,1\n"},
+			{name: "invalid value", data: "1,x\n"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := ReadCSV[int](strings.NewReader(tt.data), strconv.Atoi)
+				assert.Error(t, err, "ReadCSV() error")
+			})
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package omap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	_ fmt.Stringer   = (*Map[string, any])(nil)
+	_ fmt.GoStringer = (*Map[string, any])(nil)
+)
+
+// String implements [fmt.Stringer]. The map is formatted
+// as omap{k1:v1, k2:v2} in insertion order, and a nil map
+// is formatted as omap(nil).
+func (m *Map[K, V]) String() string {
+	if m == nil {
+		return "omap(nil)"
+	}
+	var b strings.Builder
+	b.WriteString("omap{")
+	first := true
+	for key, value := range m.All() {
+		// Write leading separator after first entry
+		if !first {
+			b.WriteString(", ")
+		}
+		fmt.Fprint(&b, key)
+		b.WriteByte(':')
+		fmt.Fprint(&b, value)
+		first = false
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// GoString implements [fmt.GoStringer]. The map is formatted as a call to
+// [New] with its entries in insertion order, with keys and values
+// formatted using the %#v verb.
+func (m *Map[K, V]) GoString() string {
+	typeArgs := reflect.TypeFor[K]().String() + ", " + reflect.TypeFor[V]().String()
+	if m == nil {
+		return "(*omap.Map[" + typeArgs + "])(nil)"
+	}
+	if m.Len() == 0 {
+		return "omap.New[" + typeArgs + "]()"
+	}
+	var b strings.Builder
+	b.WriteString("omap.New(")
+	first := true
+	for key, value := range m.All() {
+		// Write leading separator after first entry
+		if !first {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "omap.Entry[%s]{Key:%#v, Value:%#v}", typeArgs, key, value)
+		first = false
+	}
+	b.WriteByte(')')
+	return b.String()
+}
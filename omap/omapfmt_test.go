@@ -0,0 +1,90 @@
+package omap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name string
+		m    fmt.Stringer
+		want string
+	}{
+		{
+			name: "populated",
+			m: New([]Entry[string, int]{
+				{"b", 2},
+				{"a", 1},
+			}...),
+			want: "omap{b:2, a:1}",
+		},
+		{
+			name: "nested",
+			m: New([]Entry[int, *Map[string, bool]]{
+				{1, New(Entry[string, bool]{"x", true})},
+				{2, nil},
+			}...),
+			want: "omap{1:omap{x:true}, 2:omap(nil)}",
+		},
+		{
+			name: "empty",
+			m:    New[string, int](),
+			want: "omap{}",
+		},
+		{
+			name: "nil",
+			m:    (*Map[string, int])(nil),
+			want: "omap(nil)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.m.String(), "String()")
+			assert.Equal(t, tt.want, fmt.Sprintf("%v", tt.m), "Sprintf(%v)")
+			assert.Equal(t, tt.want, fmt.Sprintf("%s", tt.m), "Sprintf(%s)")
+		})
+	}
+}
+
+func TestGoString(t *testing.T) {
+	tests := []struct {
+		name string
+		m    fmt.GoStringer
+		want string
+	}{
+		{
+			name: "populated",
+			m: New([]Entry[string, int]{
+				{"b", 2},
+				{"a", 1},
+			}...),
+			want: `omap.New(omap.Entry[string, int]{Key:"b", Value:2}, omap.Entry[string, int]{Key:"a", Value:1})`,
+		},
+		{
+			name: "nested",
+			m: New([]Entry[int, *Map[string, any]]{
+				{1, New(Entry[string, any]{"x", []int{1}})},
+			}...),
+			want: `omap.New(omap.Entry[int, *omap.Map[string,interface {}]]{Key:1, Value:omap.New(omap.Entry[string, interface {}]{Key:"x", Value:[]int{1}})})`,
+		},
+		{
+			name: "empty",
+			m:    New[string, int](),
+			want: `omap.New[string, int]()`,
+		},
+		{
+			name: "nil",
+			m:    (*Map[string, int])(nil),
+			want: `(*omap.Map[string, int])(nil)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.m.GoString(), "GoString()")
+			assert.Equal(t, tt.want, fmt.Sprintf("%#v", tt.m), "Sprintf(%#v)")
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package omap
+
+import "cmp"
+
+// MapValues returns a new ordered map with the same keys and order as m,
+// with each value transformed by f. m is not modified.
+func MapValues[K cmp.Ordered, V, W any](m *Map[K, V], f func(key K, value V) W) *Map[K, W] {
+	if m == nil {
+		return nil
+	}
+	wm := NewWithCapacity[K, W](m.Len())
+	for key, value := range m.All() {
+		wm.push(key, f(key, value))
+	}
+	return wm
+}
+
+// EqualComparable reports whether a and b contain the same keys in the same
+// insertion order, with values compared using ==. A nil map is considered
+// equal to an empty map.
+func EqualComparable[K cmp.Ordered, V comparable](a, b *Map[K, V]) bool {
+	return a.Equal(b, func(x, y V) bool {
+		return x == y
+	})
+}
+
+// ContainsValueComparable reports if any value in m is equal to value,
+// using == to compare values.
+func ContainsValueComparable[K cmp.Ordered, V comparable](m *Map[K, V], value V) bool {
+	_, ok := KeyOfValueComparable(m, value)
+	return ok
+}
+
+// KeyOfValueComparable returns the first key in insertion order whose value
+// is equal to value, using == to compare values. If no value matches,
+// ok will be false.
+func KeyOfValueComparable[K cmp.Ordered, V comparable](m *Map[K, V], value V) (key K, ok bool) {
+	return m.KeyOfValue(value, func(a, b V) bool {
+		return a == b
+	})
+}
+
+// Reduce folds the entries of m in insertion order, starting with init
+// and calling f with the accumulated value and each entry in turn.
+// It returns the final accumulated value, or init if m is empty.
+func Reduce[K cmp.Ordered, V, A any](m *Map[K, V], init A, f func(acc A, key K, value V) A) A {
+	acc := init
+	for key, value := range m.All() {
+		acc = f(acc, key, value)
+	}
+	return acc
+}
+
+// GroupBy groups the entries of m into ordered maps keyed by the result of
+// calling key on each entry. Groups are ordered by when their first entry
+// appears in m, and entries within each group keep their insertion order.
+func GroupBy[K cmp.Ordered, V any, G cmp.Ordered](m *Map[K, V], key func(key K, value V) G) *Map[G, *Map[K, V]] {
+	if m == nil {
+		return nil
+	}
+	groups := New[G, *Map[K, V]]()
+	for k, v := range m.All() {
+		group := groups.GetOrCompute(key(k, v), func() *Map[K, V] {
+			return New[K, V]()
+		})
+		group.push(k, v)
+	}
+	return groups
+}
+
+// Concat returns a new ordered map containing the entries of each map in
+// turn. If a key appears in several maps, its position is set by its first
+// occurrence and its value by its last, as with [Map.Set]. Nil maps are
+// skipped. None of the maps are modified.
+func Concat[K cmp.Ordered, V any](maps ...*Map[K, V]) *Map[K, V] {
+	size := 0
+	for _, m := range maps {
+		size += m.Len()
+	}
+	cm := NewWithCapacity[K, V](size)
+	for _, m := range maps {
+		cm.Merge(m)
+	}
+	return cm
+}
+
+// Invert returns a new ordered map whose keys are the values of m and whose
+// values are the keys of m, in insertion order. If several keys have the same
+// value, the last key is kept at the position of the first, as with [Map.Set].
+func Invert[K, V cmp.Ordered](m *Map[K, V]) *Map[V, K] {
+	if m == nil {
+		return nil
+	}
+	im := NewWithCapacity[V, K](m.Len())
+	for key, value := range m.All() {
+		im.Set(value, key)
+	}
+	return im
+}
+
+// MapKeys returns a new ordered map with the same values and order as m,
+// with each key replaced by the result of calling f on its entry. If several
+// entries map to the same key, the last value is kept at the position of
+// the first, as with [Map.Set]. m is not modified.
+func MapKeys[K cmp.Ordered, V any, NK cmp.Ordered](m *Map[K, V], f func(key K, value V) NK) *Map[NK, V] {
+	if m == nil {
+		return nil
+	}
+	nm := NewWithCapacity[NK, V](m.Len())
+	for key, value := range m.All() {
+		nm.Set(f(key, value), value)
+	}
+	return nm
+}
+
+// Diff compares a and b, using eq to compare values. It returns the entries
+// of b whose keys are not in a (added), the entries of a whose keys are not
+// in b (removed), and the old and new values of keys in both whose values
+// differ (changed). added and changed are in the order of b, and removed is
+// in the order of a. A nil map is treated as an empty map.
+func Diff[K cmp.Ordered, V any](a, b *Map[K, V], eq func(a, b V) bool) (added, removed *Map[K, V], changed *Map[K, struct{ Old, New V }]) {
+	added = New[K, V]()
+	removed = New[K, V]()
+	changed = New[K, struct{ Old, New V }]()
+	for key, value := range b.All() {
+		old, ok := a.Get(key)
+		if !ok {
+			added.push(key, value)
+		} else if !eq(old, value) {
+			changed.push(key, struct{ Old, New V }{Old: old, New: value})
+		}
+	}
+	for key, value := range a.All() {
+		if !b.Has(key) {
+			removed.push(key, value)
+		}
+	}
+	return added, removed, changed
+}
+
+// Union returns a new ordered map containing the entries of a in order,
+// followed by the entries of b whose keys are not in a in the order of b.
+// For keys in both maps, the value from a is kept. A nil map is treated
+// as an empty map. Neither map is modified.
+func Union[K cmp.Ordered, V any](a, b *Map[K, V]) *Map[K, V] {
+	um := NewWithCapacity[K, V](a.Len() + b.Len())
+	for key, value := range a.All() {
+		um.push(key, value)
+	}
+	for key, value := range b.All() {
+		if !a.Has(key) {
+			um.push(key, value)
+		}
+	}
+	return um
+}
+
+// Intersect returns a new ordered map containing the entries of a whose
+// keys are also in b, in the order of a. The values from a are kept and
+// the values in b are ignored. A nil map is treated as an empty map.
+// Neither map is modified.
+func Intersect[K cmp.Ordered, V any](a, b *Map[K, V]) *Map[K, V] {
+	im := New[K, V]()
+	for key, value := range a.All() {
+		if b.Has(key) {
+			im.push(key, value)
+		}
+	}
+	return im
+}
+
+// Difference returns a new ordered map containing the entries of a whose
+// keys are not in b, in the order of a. Only the keys of b are considered,
+// not its values. A nil map is treated as an empty map. Neither map is
+// modified.
+func Difference[K cmp.Ordered, V any](a, b *Map[K, V]) *Map[K, V] {
+	dm := New[K, V]()
+	for key, value := range a.All() {
+		if !b.Has(key) {
+			dm.push(key, value)
+		}
+	}
+	return dm
+}
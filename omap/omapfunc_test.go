@@ -0,0 +1,223 @@
+package omap
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapValues(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"b", 2},
+	}...)
+	got := MapValues(m, func(key string, value int) string {
+		return key + strconv.Itoa(value)
+	})
+	assert.Equal(t, []string{"c", "a", "b"}, slices.Collect(got.Keys()), "Keys() after MapValues()")
+	assert.Equal(t, []string{"c3", "a1", "b2"}, slices.Collect(got.Values()), "Values() after MapValues()")
+	assert.Equal(t, []int{3, 1, 2}, slices.Collect(m.Values()), "original Values() after MapValues()")
+
+	assert.Nil(t, MapValues[string, int, string](nil, nil), "MapValues() on nil map")
+}
+
+func TestEqualComparable(t *testing.T) {
+	a := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+	}...)
+	assert.True(t, EqualComparable(a, a.Clone()), "EqualComparable() of identical maps")
+	assert.False(t, EqualComparable(a, a.Backward()), "EqualComparable() of maps in different order")
+	assert.False(t, EqualComparable(a, New(Entry[string, int]{"a", 1})), "EqualComparable() of maps with different lengths")
+	assert.False(t, EqualComparable(a, New([]Entry[string, int]{{"a", 1}, {"b", 3}}...)), "EqualComparable() of maps with different values")
+}
+
+func TestKeyOfValueComparable(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 1},
+		{"b", 2},
+		{"a", 1},
+	}...)
+
+	key, ok := KeyOfValueComparable(m, 1)
+	assert.True(t, ok, "KeyOfValueComparable() ok")
+	assert.Equal(t, "c", key, "KeyOfValueComparable() returns first match")
+	assert.True(t, ContainsValueComparable(m, 2), "ContainsValueComparable() for present value")
+
+	_, ok = KeyOfValueComparable(m, 3)
+	assert.False(t, ok, "KeyOfValueComparable() ok for missing value")
+	assert.False(t, ContainsValueComparable(m, 3), "ContainsValueComparable() for missing value")
+}
+
+func TestReduce(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"c", 3},
+		{"a", 1},
+		{"b", 2},
+	}...)
+
+	sum := Reduce(m, 0, func(acc int, _ string, value int) int {
+		return acc + value
+	})
+	assert.Equal(t, 6, sum, "Reduce() sum")
+
+	keys := Reduce(m, "", func(acc string, key string, _ int) string {
+		return acc + key
+	})
+	assert.Equal(t, "cab", keys, "Reduce() concatenated keys")
+
+	assert.Equal(t, 10, Reduce[string, int](nil, 10, func(acc int, _ string, value int) int {
+		return acc + value
+	}), "Reduce() on nil map")
+}
+
+func TestGroupBy(t *testing.T) {
+	m := New([]Entry[int, string]{
+		{3, "c"},
+		{1, "a"},
+		{4, "d"},
+		{2, "b"},
+		{5, "e"},
+	}...)
+	groups := GroupBy(m, func(key int, _ string) string {
+		if key%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	})
+	assert.Equal(t, []string{"odd", "even"}, slices.Collect(groups.Keys()), "group Keys()")
+	assert.Equal(t, []Entry[int, string]{{3, "c"}, {1, "a"}, {5, "e"}}, groups.Value("odd").Entries(), "odd group Entries()")
+	assert.Equal(t, []Entry[int, string]{{4, "d"}, {2, "b"}}, groups.Value("even").Entries(), "even group Entries()")
+
+	assert.Nil(t, GroupBy[int, string, string](nil, nil), "GroupBy() on nil map")
+}
+
+func TestConcat(t *testing.T) {
+	a := New([]Entry[string, int]{{"a", 1}, {"b", 2}}...)
+	b := New([]Entry[string, int]{{"c", 3}, {"a", 10}}...)
+	c := New([]Entry[string, int]{{"b", 20}, {"d", 4}}...)
+	got := Concat(a, nil, b, c)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, slices.Collect(got.Keys()), "Keys() after Concat()")
+	assert.Equal(t, []int{10, 20, 3, 4}, slices.Collect(got.Values()), "Values() after Concat()")
+	assert.Equal(t, []int{1, 2}, slices.Collect(a.Values()), "original Values() after Concat()")
+
+	assert.Equal(t, 0, Concat[string, int]().Len(), "Len() after Concat() with no maps")
+}
+
+func TestInvert(t *testing.T) {
+	m := New([]Entry[string, int]{
+		{"a", 1},
+		{"b", 2},
+		{"c", 1},
+		{"d", 3},
+	}...)
+	got := Invert(m)
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(got.Keys()), "Keys() after Invert()")
+	assert.Equal(t, []string{"c", "b", "d"}, slices.Collect(got.Values()), "Values() after Invert()")
+
+	assert.Nil(t, Invert[string, int](nil), "Invert() on nil map")
+}
+
+func TestMapKeys(t *testing.T) {
+	t.Run("prefix", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...)
+		got := MapKeys(m, func(key string, value int) string {
+			return "app." + key
+		})
+		assert.Equal(t, []string{"app.b", "app.a"}, slices.Collect(got.Keys()), "Keys() after MapKeys()")
+		assert.Equal(t, []int{2, 1}, slices.Collect(got.Values()), "Values() after MapKeys()")
+		assert.Equal(t, []string{"b", "a"}, slices.Collect(m.Keys()), "original Keys() after MapKeys()")
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"Alpha", 1},
+			{"beta", 2},
+			{"ALPHA", 3},
+		}...)
+		got := MapKeys(m, func(key string, value int) int {
+			return len(key)
+		})
+		assert.Equal(t, []int{5, 4}, slices.Collect(got.Keys()), "Keys() after MapKeys()")
+		assert.Equal(t, []int{3, 2}, slices.Collect(got.Values()), "Values() after MapKeys()")
+	})
+
+	assert.Nil(t, MapKeys[string, int, string](nil, nil), "MapKeys() on nil map")
+}
+
+func TestDiff(t *testing.T) {
+	a := New([]Entry[string, int]{
+		{"host", 1},
+		{"port", 2},
+		{"user", 3},
+		{"debug", 4},
+	}...)
+	b := New([]Entry[string, int]{
+		{"tls", 10},
+		{"port", 20},
+		{"host", 1},
+		{"debug", 40},
+		{"db", 50},
+	}...)
+	eq := func(x, y int) bool { return x == y }
+
+	added, removed, changed := Diff(a, b, eq)
+	assert.Equal(t, []Entry[string, int]{{"tls", 10}, {"db", 50}}, added.Entries(), "Diff() added")
+	assert.Equal(t, []Entry[string, int]{{"user", 3}}, removed.Entries(), "Diff() removed")
+	assert.Equal(t, []Entry[string, struct{ Old, New int }]{
+		{"port", struct{ Old, New int }{2, 20}},
+		{"debug", struct{ Old, New int }{4, 40}},
+	}, changed.Entries(), "Diff() changed")
+
+	added, removed, changed = Diff(nil, a, eq)
+	assert.Equal(t, a.Entries(), added.Entries(), "Diff() added from nil map")
+	assert.Equal(t, 0, removed.Len(), "Diff() removed from nil map")
+	assert.Equal(t, 0, changed.Len(), "Diff() changed from nil map")
+}
+
+func TestSetOperations(t *testing.T) {
+	a := New([]Entry[string, int]{
+		{"d", 4},
+		{"b", 2},
+		{"a", 1},
+	}...)
+	overlapping := New([]Entry[string, int]{
+		{"e", 50},
+		{"a", 10},
+		{"b", 20},
+	}...)
+	disjoint := New([]Entry[string, int]{
+		{"y", 25},
+		{"x", 24},
+	}...)
+
+	t.Run("overlapping", func(t *testing.T) {
+		assert.Equal(t, []Entry[string, int]{{"d", 4}, {"b", 2}, {"a", 1}, {"e", 50}}, Union(a, overlapping).Entries(), "Union()")
+		assert.Equal(t, []Entry[string, int]{{"e", 50}, {"a", 10}, {"b", 20}, {"d", 4}}, Union(overlapping, a).Entries(), "Union() reversed")
+		assert.Equal(t, []Entry[string, int]{{"b", 2}, {"a", 1}}, Intersect(a, overlapping).Entries(), "Intersect()")
+		assert.Equal(t, []Entry[string, int]{{"a", 10}, {"b", 20}}, Intersect(overlapping, a).Entries(), "Intersect() reversed")
+		assert.Equal(t, []Entry[string, int]{{"d", 4}}, Difference(a, overlapping).Entries(), "Difference()")
+		assert.Equal(t, []Entry[string, int]{{"e", 50}}, Difference(overlapping, a).Entries(), "Difference() reversed")
+	})
+
+	t.Run("disjoint", func(t *testing.T) {
+		assert.Equal(t, []Entry[string, int]{{"d", 4}, {"b", 2}, {"a", 1}, {"y", 25}, {"x", 24}}, Union(a, disjoint).Entries(), "Union()")
+		assert.Equal(t, 0, Intersect(a, disjoint).Len(), "Intersect()")
+		assert.Equal(t, a.Entries(), Difference(a, disjoint).Entries(), "Difference()")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Equal(t, a.Entries(), Union(nil, a).Entries(), "Union() with nil map")
+		assert.Equal(t, 0, Intersect(a, nil).Len(), "Intersect() with nil map")
+		assert.Equal(t, a.Entries(), Difference(a, nil).Entries(), "Difference() with nil map")
+	})
+
+	assert.Equal(t, []string{"d", "b", "a"}, slices.Collect(a.Keys()), "a Keys() after operations")
+	assert.Equal(t, []int{50, 10, 20}, slices.Collect(overlapping.Values()), "overlapping Values() after operations")
+}
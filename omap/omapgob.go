@@ -0,0 +1,37 @@
+package omap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+var (
+	_ gob.GobEncoder = (*Map[string, any])(nil)
+	_ gob.GobDecoder = (*Map[string, any])(nil)
+)
+
+// GobEncode implements [gob.GobEncoder].
+// The map is encoded as a slice of entries in insertion order.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(m.Entries()); err != nil {
+		return nil, fmt.Errorf("encoding entries: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements [gob.GobDecoder].
+// Any existing entries in m are replaced by the decoded entries.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	var entries []Entry[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entries); err != nil {
+		return fmt.Errorf("decoding entries: %w", err)
+	}
+	m.Clear()
+	m.Grow(len(entries))
+	for _, entry := range entries {
+		m.Set(entry.Key, entry.Value)
+	}
+	return nil
+}
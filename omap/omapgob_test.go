@@ -0,0 +1,61 @@
+package omap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGob(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"z", 26},
+			{"a", 1},
+			{"m", 13},
+		}...)
+		m.Delete("a")
+		m.Set("b", 2)
+
+		buf := new(bytes.Buffer)
+		err := gob.NewEncoder(buf).Encode(m)
+		assert.NoError(t, err, "Encode() error")
+
+		got := New(Entry[string, int]{"x", 1})
+		err = gob.NewDecoder(buf).Decode(got)
+		assert.NoError(t, err, "Decode() error")
+		assert.Equal(t, []string{"z", "m", "b"}, slices.Collect(got.Keys()), "Keys() after Decode()")
+		assert.True(t, m.Equal(got, func(a, b int) bool { return a == b }), "Decode() output")
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		type record struct {
+			Name  string
+			Attrs *Map[int, string]
+		}
+		want := record{
+			Name: "test",
+			Attrs: New([]Entry[int, string]{
+				{3, "c"},
+				{1, "a"},
+				{2, "b"},
+			}...),
+		}
+
+		buf := new(bytes.Buffer)
+		err := gob.NewEncoder(buf).Encode(want)
+		assert.NoError(t, err, "Encode() error")
+
+		var got record
+		err = gob.NewDecoder(buf).Decode(&got)
+		assert.NoError(t, err, "Decode() error")
+		assert.Equal(t, want, got, "Decode() output")
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		err := New[string, int]().GobDecode([]byte("invalid"))
+		assert.ErrorContains(t, err, "decoding entries", "GobDecode() error")
+	})
+}
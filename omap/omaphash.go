@@ -0,0 +1,49 @@
+package omap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+)
+
+// Hash returns a 64-bit FNV-1a hash of the entries of m in insertion order,
+// using hashValue to hash each value. The hash depends on the order of the
+// entries, so maps with the same entries in different orders usually have
+// different hashes. The hash of a given map is the same across runs and
+// platforms, as long as hashValue is. A nil map has the same hash as an
+// empty map.
+func (m *Map[K, V]) Hash(hashValue func(value V) uint64) uint64 {
+	h := fnv.New64a()
+	for key, value := range m.All() {
+		writeHashEntry(h, key, hashValue(value))
+	}
+	return h.Sum64()
+}
+
+// ContentHash returns a 64-bit hash of the entries of m that does not depend
+// on their order, using hashValue to hash each value. Each entry is hashed
+// as in [Map.Hash], and the entry hashes are added together, so maps with the
+// same entries in any order have the same content hash. The content hash of
+// a given map is the same across runs and platforms, as long as hashValue is.
+// A nil map has the same content hash as an empty map.
+func (m *Map[K, V]) ContentHash(hashValue func(value V) uint64) uint64 {
+	var sum uint64
+	h := fnv.New64a()
+	for key, value := range m.All() {
+		h.Reset()
+		writeHashEntry(h, key, hashValue(value))
+		sum += h.Sum64()
+	}
+	return sum
+}
+
+// writeHashEntry writes a key and the hash of its value to h. The key is
+// prefixed with its length so that adjacent keys cannot run together.
+func writeHashEntry(h hash.Hash64, key any, valueHash uint64) {
+	keyString := fmt.Sprint(key)
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(binary.AppendUvarint(buf[:0], uint64(len(keyString))))
+	h.Write([]byte(keyString))
+	h.Write(binary.LittleEndian.AppendUint64(buf[:0], valueHash))
+}
@@ -0,0 +1,62 @@
+package omap
+
+import (
+	"hash/fnv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func TestHash(t *testing.T) {
+	a := New([]Entry[string, string]{
+		{"a", "1"},
+		{"b", "2"},
+	}...)
+	b := New([]Entry[string, string]{
+		{"b", "2"},
+		{"a", "1"},
+	}...)
+	assert.Equal(t, a.Hash(hashString), a.Clone().Hash(hashString), "Hash() of clone")
+	assert.NotEqual(t, a.Hash(hashString), b.Hash(hashString), "Hash() of maps in different orders")
+
+	// The hash must not change between runs or releases
+	assert.Equal(t, uint64(0xcfffae1639b5bf6e), a.Hash(hashString), "Hash() value")
+
+	// Keys must not run together
+	c := New([]Entry[string, string]{{"ab", ""}, {"c", ""}}...)
+	d := New([]Entry[string, string]{{"a", ""}, {"bc", ""}}...)
+	assert.NotEqual(t, c.Hash(hashString), d.Hash(hashString), "Hash() of maps with adjacent keys")
+
+	var nilMap *Map[string, string]
+	assert.Equal(t, New[string, string]().Hash(hashString), nilMap.Hash(hashString), "Hash() of nil map")
+}
+
+func TestContentHash(t *testing.T) {
+	a := New([]Entry[string, string]{
+		{"a", "1"},
+		{"b", "2"},
+		{"c", "3"},
+	}...)
+	b := New([]Entry[string, string]{
+		{"c", "3"},
+		{"a", "1"},
+		{"b", "2"},
+	}...)
+	assert.Equal(t, a.ContentHash(hashString), b.ContentHash(hashString), "ContentHash() of maps in different orders")
+	assert.NotEqual(t, a.Hash(hashString), b.Hash(hashString), "Hash() of maps in different orders")
+
+	b.Set("c", "30")
+	assert.NotEqual(t, a.ContentHash(hashString), b.ContentHash(hashString), "ContentHash() of maps with different values")
+	b.Set("c", "3")
+	b.Set("d", "4")
+	assert.NotEqual(t, a.ContentHash(hashString), b.ContentHash(hashString), "ContentHash() of maps with different keys")
+
+	var nilMap *Map[string, string]
+	assert.Equal(t, uint64(0), nilMap.ContentHash(hashString), "ContentHash() of nil map")
+}
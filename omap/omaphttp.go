@@ -0,0 +1,84 @@
+package omap
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AddValue appends value to the values for key in m, which is treated
+// as an ordered multimap. If the key does not exist, it is added to the
+// back of the order. AddValue panics if m is nil.
+func AddValue[K cmp.Ordered, V any](m *Map[K, []V], key K, value V) {
+	if m == nil {
+		panic("omap: AddValue called on nil *Map")
+	}
+	if e, ok := m.entries[key]; ok {
+		e.value = append(e.value, value)
+		return
+	}
+	m.push(key, []V{value})
+}
+
+// GetFirst returns the first value for key in m, which is treated as an
+// ordered multimap. If the key does not exist or has no values, ok will
+// be false and value will be the zero value of its type.
+func GetFirst[K cmp.Ordered, V any](m *Map[K, []V], key K) (value V, ok bool) {
+	values := m.Value(key)
+	if len(values) == 0 {
+		return value, false
+	}
+	return values[0], true
+}
+
+// headerNewlineReplacer replaces line breaks in header values, as
+// [net/http.Header.Write] does, so a value cannot start a new header.
+var headerNewlineReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// WriteHTTPHeader writes the entries of m to w in HTTP/1.1 wire format,
+// with a "Key: value" line for each value of each key. Unlike
+// [net/http.Header.Write], the keys are written in insertion order and
+// are not canonicalized, and values are written in the order they were
+// added. Line breaks in values are replaced with spaces.
+//
+// WriteHTTPHeader returns an error without writing anything if a key is
+// not a valid header field name, which must be a non-empty token as
+// defined by RFC 9110, so that a key cannot inject another header.
+func WriteHTTPHeader(m *Map[string, []string], w io.Writer) error {
+	for key := range m.Keys() {
+		if !validHeaderFieldName(key) {
+			return fmt.Errorf("invalid header field name %q", key)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for key, values := range m.All() {
+		for _, value := range values {
+			bw.WriteString(key)
+			bw.WriteString(": ")
+			headerNewlineReplacer.WriteString(bw, strings.TrimSpace(value))
+			bw.WriteString("\r\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// validHeaderFieldName reports whether name is a valid header field name,
+// which is a token as defined by RFC 9110, Section 5.6.2.
+func validHeaderFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
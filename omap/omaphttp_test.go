@@ -0,0 +1,53 @@
+package omap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPHeader(t *testing.T) {
+	h := New[string, []string]()
+	AddValue(h, "Host", "example.com")
+	AddValue(h, "Accept", "text/html")
+	AddValue(h, "User-Agent", "omap")
+	AddValue(h, "Accept", "application/json")
+	AddValue(h, "X-Note", "line one\nline two")
+
+	assert.Equal(t, []string{"Host", "Accept", "User-Agent", "X-Note"}, h.KeysSlice(), "KeysSlice() after AddValue()")
+	assert.Equal(t, []string{"text/html", "application/json"}, h.Value("Accept"), "Value() after AddValue()")
+
+	first, ok := GetFirst(h, "Accept")
+	assert.True(t, ok, "GetFirst() ok")
+	assert.Equal(t, "text/html", first, "GetFirst()")
+	_, ok = GetFirst(h, "Missing")
+	assert.False(t, ok, "GetFirst() ok for missing key")
+
+	var sb strings.Builder
+	assert.NoError(t, WriteHTTPHeader(h, &sb), "WriteHTTPHeader() error")
+	want := "Host: example.com\r\n" +
+		"Accept: text/html\r\n" +
+		"Accept: application/json\r\n" +
+		"User-Agent: omap\r\n" +
+		"X-Note: line one line two\r\n"
+	assert.Equal(t, want, sb.String(), "WriteHTTPHeader() output")
+
+	t.Run("invalid field names", func(t *testing.T) {
+		for _, key := range []string{"X-A\r\nInjected", "X-A\nB", "X-A: B", "X A", " X-A", "X-A\t", "", "X-\u00e9"} {
+			h := New[string, []string]()
+			AddValue(h, "Host", "example.com")
+			AddValue(h, key, "v")
+			var sb strings.Builder
+			err := WriteHTTPHeader(h, &sb)
+			assert.EqualError(t, err, fmt.Sprintf("invalid header field name %q", key), "WriteHTTPHeader() error for key %q", key)
+			assert.Empty(t, sb.String(), "WriteHTTPHeader() output for key %q", key)
+		}
+	})
+
+	var nilMap *Map[string, []string]
+	assert.PanicsWithValue(t, "omap: AddValue called on nil *Map", func() {
+		AddValue(nilMap, "a", "b")
+	}, "AddValue() on nil map")
+}
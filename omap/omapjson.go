@@ -1,59 +1,533 @@
 package omap
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
+	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"reflect"
+	"io"
+	"sync"
+
+	"github.com/justenstall/omap/omap/internal/jsonkey"
 )
 
 var (
-	_ json.Marshaler   = (*Map[string, any])(nil)
-	_ json.Unmarshaler = (*Map[string, any])(nil)
+	_ json.Marshaler           = (*Map[string, any])(nil)
+	_ json.Unmarshaler         = (*Map[string, any])(nil)
+	_ encoding.TextMarshaler   = (*Map[string, any])(nil)
+	_ encoding.TextUnmarshaler = (*Map[string, any])(nil)
 )
 
 // MarshalJSON implements [json.Marshaler].
+//
+// A nil map is marshaled as null, while an empty map is marshaled as {}.
+// When a *Map is a struct field, the omitempty option only omits nil maps.
+// With Go 1.24 or later, use the omitzero option to also omit empty maps,
+// since it calls [Map.IsZero]. To marshal a nil map as {}, use [Map.MarshalJSONObject].
+//
+// If the map contains itself, directly or through the ordered maps and
+// []any values nested in it, MarshalJSON returns a [*json.UnsupportedValueError]
+// rather than recursing without end. Other values are encoded by
+// [json.Marshal], which does not pass on the state needed to detect
+// cycles through them.
 func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return m.MarshalJSONOptions(EncodeOptions{})
+}
+
+// MarshalJSONOptions is like [Map.MarshalJSON], but encodes
+// the object as configured by opts.
+func (m *Map[K, V]) MarshalJSONOptions(opts EncodeOptions) ([]byte, error) {
 	if m == nil {
 		return []byte(`null`), nil
 	}
+	return m.marshalJSON(opts)
+}
+
+// EncodeOptions configures how ordered maps are encoded as JSON objects.
+// The zero value is the default behavior of [Map.MarshalJSON].
+type EncodeOptions struct {
+	// DisableHTMLEscaping stops <, >, and & from being escaped in keys
+	// and values, as [json.Encoder.SetEscapeHTML] does when set to false.
+	// By default, both are escaped as they are by [json.Marshal].
+	// The option applies to ordered maps nested directly as values or
+	// within []any values, but not to those nested within other types,
+	// which use [Map.MarshalJSON].
+	DisableHTMLEscaping bool
+}
+
+// stateEncoder is implemented by the ordered maps so that nested maps
+// are encoded with the same options and cycle detection as their parent.
+type stateEncoder interface {
+	encodeJSONState(w jsonWriter, st *encodeState) error
+}
+
+// encodeJSONState implements stateEncoder.
+func (m *Map[K, V]) encodeJSONState(w jsonWriter, st *encodeState) error {
+	if m == nil {
+		w.Write([]byte(`null`))
+		return nil
+	}
+	return m.encodeJSON(w, st)
+}
+
+// MarshalJSONIndent is like [Map.MarshalJSON], but applies [json.Indent]
+// to format the output. Each entry begins on a new line starting with prefix
+// followed by one or more copies of indent according to the nesting depth.
+// Nested ordered maps keep their order.
+func (m *Map[K, V]) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
 	buf := new(bytes.Buffer)
+	if err := json.Indent(buf, data, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeJSON writes m to w as a JSON object, encoding one entry at a time
+// through a buffered writer rather than building the whole object in memory.
+// The output is the same as [Map.MarshalJSON].
+func (m *Map[K, V]) EncodeJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if m == nil {
+		bw.WriteString(`null`)
+	} else {
+		st := newEncodeState(EncodeOptions{})
+		defer st.release()
+		if err := m.encodeJSON(bw, st); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// MarshalJSONObject is like [Map.MarshalJSON], but marshals
+// a nil map as an empty JSON object instead of null.
+func (m *Map[K, V]) MarshalJSONObject() ([]byte, error) {
+	if m == nil {
+		return []byte(`{}`), nil
+	}
+	return m.MarshalJSON()
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// Any existing entries in m are replaced by the decoded object.
+// Decoding null leaves m unchanged. It uses the default [DecodeOptions].
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	return m.unmarshalJSON(data, DecodeOptions{})
+}
+
+// UnmarshalJSONOptions is like [Map.UnmarshalJSON], but decodes
+// the object as configured by opts.
+func (m *Map[K, V]) UnmarshalJSONOptions(data []byte, opts DecodeOptions) error {
+	return m.unmarshalJSON(data, opts)
+}
+
+// DecodeOptions configures how JSON objects are decoded into ordered maps.
+// The zero value is the default behavior of [Map.UnmarshalJSON].
+// Options also apply to objects nested in values of type any.
+type DecodeOptions struct {
+	// DuplicateKeys determines how a key appearing more
+	// than once in the same object is handled.
+	DuplicateKeys DuplicateKeyPolicy
+	// UseNumber decodes numbers in values of type any as
+	// [json.Number] instead of float64, as [json.Decoder.UseNumber]
+	// does, so large integers keep their precision.
+	UseNumber bool
+	// MaxDepth is the maximum nesting depth of objects and arrays, counting
+	// the top-level object as depth 1. Decoding more deeply nested input
+	// returns an error rather than risking exhausting the stack.
+	// If MaxDepth is 0, [DefaultMaxDepth] is used. Values above 10000
+	// have no effect, as encoding/json enforces that limit itself.
+	MaxDepth int
+}
+
+// DefaultMaxDepth is the maximum nesting depth used when
+// [DecodeOptions.MaxDepth] is 0.
+const DefaultMaxDepth = 1000
+
+// errMaxDepth is returned when input is nested more deeply than allowed.
+var errMaxDepth = errors.New("maximum nesting depth exceeded")
+
+// maxDepth returns the maximum nesting depth configured by o.
+func (o DecodeOptions) maxDepth() int {
+	if o.MaxDepth > 0 {
+		return o.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+// DuplicateKeyPolicy determines how duplicate keys in a JSON object are decoded.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysOverwrite sets the value of a duplicate key,
+	// keeping the position of its first occurrence, as with [Map.Set].
+	// This is the default.
+	DuplicateKeysOverwrite DuplicateKeyPolicy = iota
+	// DuplicateKeysMoveToEnd sets the value of a duplicate key
+	// and moves it to the position of its last occurrence.
+	DuplicateKeysMoveToEnd
+	// DuplicateKeysError returns an error for a duplicate key.
+	DuplicateKeysError
+)
+
+// DecodeJSON decodes a JSON object from r into a new ordered map,
+// reading the entries one at a time rather than the whole input at once.
+// It returns an error if the first value in r is not a JSON object.
+// Any data following the object is ignored, but since r is read through
+// a [json.Decoder], which reads ahead into its own buffer, DecodeJSON may
+// consume some or all of it from r.
+func DecodeJSON[K cmp.Ordered, V any](r io.Reader) (*Map[K, V], error) {
+	d := json.NewDecoder(r)
+
+	// Consume the '{' delimiter
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("cannot decode %v as JSON object", tok)
+	}
+
+	// Decode entries until complete
+	m := New[K, V]()
+	if err := m.decodeObject(d, DecodeOptions{}, 1); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeObject decodes the entries of a JSON object at the given nesting
+// depth from d into l as configured by opts. The opening '{' delimiter
+// must already have been consumed.
+func (l *list[K, V]) decodeObject(d *json.Decoder, opts DecodeOptions, depth int) error {
+	for d.More() {
+		var (
+			key   K
+			value V
+		)
+
+		// Decode the key as a string
+		var keyString string
+		if err := d.Decode(&keyString); err != nil {
+			return fmt.Errorf("unmarshalling key string: %w", err)
+		}
+		// Parse the key as its native type
+		if err := jsonkey.Parse(keyString, &key); err != nil {
+			return fmt.Errorf("parsing key %q as %T: %w", keyString, key, err)
+		}
+		// Decode the value
+		if err := decodeValue(d, &value, opts, depth); err != nil {
+			if errors.Is(err, errMaxDepth) {
+				// Avoid wrapping the error once for each level
+				return err
+			}
+			return fmt.Errorf("unmarshalling value (type %T): %w", value, err)
+		}
+
+		// Set the value in the map
+		if _, ok := l.entries[key]; !ok {
+			l.push(key, value)
+			continue
+		}
+		switch opts.DuplicateKeys {
+		case DuplicateKeysMoveToEnd:
+			l.pushElement(l.detach(key, value))
+		case DuplicateKeysError:
+			return fmt.Errorf("duplicate key %q", keyString)
+		default:
+			l.set(key, value)
+		}
+	}
+
+	// Consume the '}' delimiter
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// marshalJSON marshals the entries of l as a JSON object as configured by opts.
+func (l *list[K, V]) marshalJSON(opts EncodeOptions) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}
+	}()
+	st := newEncodeState(opts)
+	defer st.release()
+	if err := l.encodeJSON(buf, st); err != nil {
+		return nil, err
+	}
+	// Copy the output, since the buffer is reused
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// jsonWriter is a writer that records write errors until they are
+// checked, such as [bufio.Writer], or never fails, such as [bytes.Buffer].
+type jsonWriter interface {
+	io.Writer
+	io.ByteWriter
+}
+
+// encodeJSON writes the entries of l to w as a JSON object one at a time
+// as configured by st. Errors from writing to w are not checked.
+func (l *list[K, V]) encodeJSON(w jsonWriter, st *encodeState) error {
+	if err := st.enter(l); err != nil {
+		return err
+	}
+	defer st.leave(l)
+
 	// Opening bracket
-	buf.WriteByte('{')
+	w.WriteByte('{')
 	first := true
-	for key, value := range m.All() {
-		// Marshal the key
-		keyJSON, err := marshalKey(key)
-		if err != nil {
-			return nil, fmt.Errorf("marshalling key (type %T): %w", key, err)
+	for _, e := range l.order {
+		if e == nil {
+			continue
 		}
+		key, value := e.key, e.value
 
-		// Marshal the value
-		valueJSON, err := json.Marshal(value)
+		// Marshal the key
+		keyJSON, err := jsonkey.Marshal(key)
 		if err != nil {
-			return nil, fmt.Errorf("marshalling value (type %T): %w", value, err)
+			return fmt.Errorf("marshalling key (type %T): %w", key, err)
+		}
+		if st.opts.DisableHTMLEscaping {
+			keyJSON = st.unescapeHTML(keyJSON)
 		}
 
 		// Write leading comma after first value
 		if !first {
-			buf.WriteByte(',')
+			w.WriteByte(',')
 		}
 
 		// Write key and value joined by colon
-		buf.Write(keyJSON)
-		buf.WriteByte(':')
-		buf.Write(valueJSON)
+		w.Write(keyJSON)
+		w.WriteByte(':')
+		if err := st.encodeValue(w, value); err != nil {
+			if errors.Is(err, errCycle) {
+				// Avoid wrapping the error once for each level
+				return errCycle
+			}
+			return fmt.Errorf("marshalling value (type %T): %w", value, err)
+		}
 
 		// Mark as not the first
 		first = false
 	}
 	// Closing bracket
-	buf.WriteByte('}')
-	return buf.Bytes(), nil
+	w.WriteByte('}')
+	return nil
 }
 
-// UnmarshalJSON implements [json.Unmarshaler].
-func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+// encodeState holds the state of one call encoding an ordered map as
+// JSON, which is shared with the ordered maps and arrays nested in it.
+type encodeState struct {
+	valueEncoder
+	opts EncodeOptions
+	// depth is the number of ordered maps and arrays being encoded.
+	depth int
+	// seen holds the ordered maps and arrays being encoded
+	// once depth passes startDetectingCyclesAfter.
+	seen map[any]struct{}
+}
+
+// startDetectingCyclesAfter is the nesting depth after which encodeState
+// starts to track the ordered maps and arrays being encoded to detect
+// cycles, so that shallow values are encoded without the overhead, as
+// in encoding/json.
+const startDetectingCyclesAfter = 1000
+
+// errCycle is returned when a map contains itself, as [json.Marshal]
+// does for cyclic data structures.
+var errCycle error = &json.UnsupportedValueError{Str: "encountered a cycle"}
+
+// newEncodeState returns an encodeState from the pool for encoding
+// with opts. It should be returned with release once encoding is done.
+func newEncodeState(opts EncodeOptions) *encodeState {
+	st := encodeStatePool.Get().(*encodeState)
+	st.opts = opts
+	return st
+}
+
+// release returns st to the pool.
+func (st *encodeState) release() {
+	if st.buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	st.depth = 0
+	clear(st.seen)
+	encodeStatePool.Put(st)
+}
+
+// enter records that the ordered map or array identified by ptr is being
+// encoded, returning errCycle if it already is. Each successful call
+// must be followed by a call to leave.
+func (st *encodeState) enter(ptr any) error {
+	st.depth++
+	if st.depth <= startDetectingCyclesAfter {
+		return nil
+	}
+	if _, ok := st.seen[ptr]; ok {
+		st.depth--
+		return errCycle
+	}
+	if st.seen == nil {
+		st.seen = make(map[any]struct{})
+	}
+	st.seen[ptr] = struct{}{}
+	return nil
+}
+
+// leave records that the value identified by ptr is done being encoded.
+func (st *encodeState) leave(ptr any) {
+	if st.depth > startDetectingCyclesAfter {
+		delete(st.seen, ptr)
+	}
+	st.depth--
+}
+
+// unescapeHTML rewrites the escapes that encoding/json uses for <, >,
+// and & in a JSON string as the characters themselves, as if it had been
+// encoded with HTML escaping disabled. The result uses the buffer of st
+// and is only valid until the next call to marshal, so it must be written
+// before the value that follows it is encoded.
+func (st *encodeState) unescapeHTML(stringJSON []byte) []byte {
+	if !bytes.Contains(stringJSON, []byte(`\u00`)) {
+		return stringJSON
+	}
+	st.buf.Reset()
+	for i := 0; i < len(stringJSON); i++ {
+		if stringJSON[i] != '\\' || i+1 == len(stringJSON) {
+			st.buf.WriteByte(stringJSON[i])
+			continue
+		}
+		if rest := stringJSON[i+1:]; len(rest) >= 5 && rest[0] == 'u' {
+			switch string(rest[1:5]) {
+			case "003c":
+				st.buf.WriteByte('<')
+				i += 5
+				continue
+			case "003e":
+				st.buf.WriteByte('>')
+				i += 5
+				continue
+			case "0026":
+				st.buf.WriteByte('&')
+				i += 5
+				continue
+			}
+		}
+		// Copy other escapes whole, so that an escaped
+		// backslash is not read as the start of an escape
+		st.buf.Write(stringJSON[i : i+2])
+		i++
+	}
+	return st.buf.Bytes()
+}
+
+// encodeValue writes value to w as JSON. Ordered maps and []any values
+// are encoded with st, so that cycles through them are detected, and
+// other values are encoded by encoding/json.
+func (st *encodeState) encodeValue(w jsonWriter, value any) error {
+	switch v := value.(type) {
+	case stateEncoder:
+		return v.encodeJSONState(w, st)
+	case []any:
+		return st.encodeArray(w, v)
+	}
+	valueJSON, err := st.marshal(value, st.opts)
+	if err != nil {
+		return err
+	}
+	w.Write(valueJSON)
+	return nil
+}
+
+// encodeArray writes s to w as a JSON array.
+func (st *encodeState) encodeArray(w jsonWriter, s []any) error {
+	if s == nil {
+		w.Write([]byte(`null`))
+		return nil
+	}
+	if len(s) > 0 {
+		// Identify the array by its first element and length, as
+		// encoding/json does, since slices of different lengths
+		// can share their first element
+		ptr := struct {
+			first *any
+			len   int
+		}{&s[0], len(s)}
+		if err := st.enter(ptr); err != nil {
+			return err
+		}
+		defer st.leave(ptr)
+	}
+
+	w.WriteByte('[')
+	for i, v := range s {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		if err := st.encodeValue(w, v); err != nil {
+			return err
+		}
+	}
+	w.WriteByte(']')
+	return nil
+}
+
+// valueEncoder encodes JSON values into a reusable buffer.
+type valueEncoder struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// marshal marshals a value as JSON as configured by opts.
+// The result is only valid until the next call to marshal.
+func (e *valueEncoder) marshal(value any, opts EncodeOptions) ([]byte, error) {
+	e.buf.Reset()
+	e.enc.SetEscapeHTML(!opts.DisableHTMLEscaping)
+	if err := e.enc.Encode(value); err != nil {
+		return nil, err
+	}
+	// Remove the newline added by Encode
+	return bytes.TrimSuffix(e.buf.Bytes(), []byte("\n")), nil
+}
+
+// maxPooledBufferSize is the largest buffer capacity kept in the pools,
+// so that marshaling one very large map does not pin its memory.
+const maxPooledBufferSize = 64 << 10
+
+var (
+	// bufferPool holds buffers for the output of marshalJSON.
+	bufferPool = sync.Pool{
+		New: func() any { return new(bytes.Buffer) },
+	}
+	// encodeStatePool holds the states for encodeJSON.
+	encodeStatePool = sync.Pool{
+		New: func() any {
+			st := new(encodeState)
+			st.enc = json.NewEncoder(&st.buf)
+			return st
+		},
+	}
+)
+
+// unmarshalJSON replaces the entries of l with those decoded from a JSON
+// object as configured by opts.
+func (l *list[K, V]) unmarshalJSON(data []byte, opts DecodeOptions) error {
+	// Ignore surrounding whitespace
+	data = bytes.TrimSpace(data)
 	// Empty input
 	if len(data) == 0 || bytes.Equal(data, []byte(`null`)) {
 		return nil
@@ -63,10 +537,16 @@ func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("cannot parse %s as JSON object", string(data))
 	}
 
+	// Discard any existing entries
+	l.clear()
+
 	// Create a JSON decoder to read within the object
 	// data = data[1 : len(data)-1] // remove leading and trailing bytes
 	r := bytes.NewReader(data)
 	d := json.NewDecoder(r)
+	if opts.UseNumber {
+		d.UseNumber()
+	}
 
 	// Consume the '{' delimiter
 	if _, err := d.Token(); err != nil {
@@ -74,105 +554,88 @@ func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
 	}
 
 	// Decode entries until complete
-	for d.More() {
-		var (
-			key   K
-			value V
-		)
-
-		// Decode the key as a string
-		var keyString string
-		if err := d.Decode(&keyString); err != nil {
-			return fmt.Errorf("unmarshalling key string: %w", err)
-		}
-		// Parse the key as its native type
-		if err := parseKey(keyString, &key); err != nil {
-			return fmt.Errorf("parsing key as type %T: %w", key, err)
-		}
-		// Decode the value
-		if err := d.Decode(&value); err != nil {
-			return fmt.Errorf("unmarshalling value (type %T): %w", value, err)
-		}
-
-		// Set the value in the map
-		m.Set(key, value)
+	if err := l.decodeObject(d, opts, 1); err != nil {
+		return err
 	}
 
+	// Reject anything after the closing '}' delimiter
+	offset := d.InputOffset()
+	if _, err := d.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after JSON object at offset %d", offset)
+	}
 	return nil
 }
 
-// marshalKey marshals a key as a JSON string.
-func marshalKey(key any) ([]byte, error) {
-	keyJSON, err := json.Marshal(key)
+// decodeValue decodes the next JSON value from d into value, where depth
+// is the nesting depth of the object or array containing it.
+// If value is a pointer to an empty interface, JSON objects are
+// decoded as *Map[string, any] to preserve their order.
+func decodeValue[V any](d *json.Decoder, value *V, opts DecodeOptions, depth int) error {
+	if v, ok := any(value).(*any); ok {
+		var err error
+		*v, err = decodeAny(d, opts, depth)
+		return err
+	}
+	return d.Decode(value)
+}
+
+// decodeAny decodes the next JSON value from d, where depth is the nesting
+// depth of the object or array containing it. JSON objects are decoded
+// as *Map[string, any] as configured by opts, including objects nested
+// within arrays and objects. All other values are decoded as they would
+// be by [json.Unmarshal].
+func decodeAny(d *json.Decoder, opts DecodeOptions, depth int) (any, error) {
+	tok, err := d.Token()
 	if err != nil {
 		return nil, err
 	}
-	if len(keyJSON) == 0 {
-		// Return empty JSON string
-		return []byte(`""`), nil
-	}
-	// Check first character of JSON representation
-	switch keyJSON[0] {
-	case '"':
-		// JSON value is a string, return as-is
-		return keyJSON, nil
-	case '[', '{':
-		// JSON value is an array or object, return error
-		return nil, fmt.Errorf("unsupported key type: %T", key)
-	default:
-		// JSON value is a number, boolean, or null
-		// Format the key as a string (JSON only supports string keys in mappings)
-		keyJSON, err = json.Marshal(string(keyJSON))
-		if err != nil {
-			return nil, fmt.Errorf("formatting as string: %w", err)
-		}
-		return keyJSON, nil
-	}
-}
-
-// parseKey parses a string into key.
-// key must be a pointer to a type whose underyling type
-// satisfies cmp.Ordered.
-func parseKey(keyString string, key any) error {
-	// Handle all types in cmp.Ordered
-	switch typedKey := any(key).(type) {
-	case *int, *int8, *int16, *int32, *int64,
-		*uint, *uint8, *uint16, *uint32, *uint64, *uintptr,
-		*float32, *float64:
-		// Unmarshal as JSON for non-string types
-		return json.Unmarshal([]byte(keyString), typedKey)
-	case *string:
-		// Store string and return
-		*typedKey = keyString
-		return nil
-	default:
-		// Fall back to using reflection to check the
-		// underlying type of the key.
-		v := reflect.ValueOf(key)
-		if v.Kind() != reflect.Pointer {
-			// Key must be a pointer
-			return fmt.Errorf("unsupported key type: %T", key)
-		}
-
-		// We know that key is a pointer, so call Elem()
-		// to get the element type.
-		switch v.Elem().Kind() {
-		// Numeric types
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-			reflect.Float32, reflect.Float64:
-			// Unmarshal as JSON for non-string types
-			return json.Unmarshal([]byte(keyString), key)
-		// String types
-		case reflect.String:
-			v.Elem().SetString(keyString)
-			return nil
-		default:
-			return fmt.Errorf("unsupported key type: %T", key)
+	if tok == json.Delim('{') || tok == json.Delim('[') {
+		depth++
+		if depth > opts.maxDepth() {
+			return nil, errMaxDepth
+		}
+	}
+	switch tok {
+	case json.Delim('{'):
+		// Decode objects as ordered maps
+		m := New[string, any]()
+		if err := m.decodeObject(d, opts, depth); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case json.Delim('['):
+		// Decode each array element
+		s := []any{}
+		for d.More() {
+			v, err := decodeAny(d, opts, depth)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
 		}
+		// Consume the ']' delimiter
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		// Strings, numbers, booleans, and null
+		return tok, nil
 	}
 }
 
+// MarshalText implements [encoding.TextMarshaler].
+// The text is the same JSON object produced by [Map.MarshalJSON].
+func (m *Map[K, V]) MarshalText() ([]byte, error) {
+	return m.MarshalJSON()
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+// The text is parsed as a JSON object as in [Map.UnmarshalJSON].
+func (m *Map[K, V]) UnmarshalText(text []byte) error {
+	return m.UnmarshalJSON(text)
+}
+
 // // cutByte slices s around the first instance of sep,
 // // returning the text before and after sep.
 // // The found result reports whether sep appears in s.
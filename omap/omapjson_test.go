@@ -1,17 +1,23 @@
 package omap
 
 import (
+	"bytes"
 	"cmp"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func ptrTo[T any](v T) *T {
-	return &v
-}
-
 func testUnmarshal[K cmp.Ordered, V any](t *testing.T, data string, want *Map[K, V], wantErrString string) {
 	t.Helper()
 	got := &Map[K, V]{}
@@ -34,92 +40,653 @@ func TestUnmarshal(t *testing.T) {
 		}...)
 		testUnmarshal(t, data, want, "")
 	})
+
+	t.Run("replaces existing entries", func(t *testing.T) {
+		m := New(Entry[string, int]{"x", 1})
+		err := json.Unmarshal([]byte(`{"b":2,"a":1}`), m)
+		assert.NoError(t, err, "json.Unmarshal() error")
+		assert.Equal(t, New([]Entry[string, int]{{"b", 2}, {"a", 1}}...), m, "json.Unmarshal() output")
+		assert.False(t, m.Has("x"), "Has() for previous key")
+	})
+
+	t.Run("null leaves map unchanged", func(t *testing.T) {
+		m := New(Entry[string, int]{"x", 1})
+		err := m.UnmarshalJSON([]byte(`null`))
+		assert.NoError(t, err, "UnmarshalJSON() error")
+		assert.Equal(t, New(Entry[string, int]{"x", 1}), m, "UnmarshalJSON() output")
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			data    string
+			wantErr string
+		}{
+			{name: "terminated", data: `{"a":1}`},
+			{name: "trailing whitespace", data: "{\"a\":1}\n"},
+			{name: "doubled closing brace", data: `{"a":1}}`, wantErr: "unexpected data after JSON object at offset 7"},
+			{name: "second object", data: `{"a":1}{"b":2}`, wantErr: "unexpected data after JSON object at offset 7"},
+			{name: "trailing garbage", data: `{"a":1}garbage`, wantErr: "cannot parse {\"a\":1}garbage as JSON object"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := New[string, int]()
+				err := m.UnmarshalJSON([]byte(tt.data))
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr, "UnmarshalJSON() error")
+					return
+				}
+				assert.NoError(t, err, "UnmarshalJSON() error")
+				assert.Equal(t, []Entry[string, int]{{"a", 1}}, m.Entries(), "Entries() after UnmarshalJSON()")
+			})
+		}
+	})
+}
+
+func TestUnmarshalNested(t *testing.T) {
+	t.Run("objects", func(t *testing.T) {
+		data := `{"a":{"z":1,"y":2},"b":"x","c":null}`
+		m := New[string, any]()
+		err := json.Unmarshal([]byte(data), m)
+		assert.NoError(t, err, "json.Unmarshal() error")
+
+		inner, ok := m.Value("a").(*Map[string, any])
+		if assert.True(t, ok, "nested value type") {
+			assert.Equal(t, []string{"z", "y"}, slices.Collect(inner.Keys()), "nested Keys()")
+			assert.Equal(t, []any{float64(1), float64(2)}, slices.Collect(inner.Values()), "nested Values()")
+		}
+
+		got, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, data, string(got), "json.Marshal() output")
+	})
+
+	t.Run("arrays of objects", func(t *testing.T) {
+		data := `{"list":[{"z":1,"y":[{"c":true,"b":false}]},2,"three"]}`
+		m := New[string, any]()
+		err := json.Unmarshal([]byte(data), m)
+		assert.NoError(t, err, "json.Unmarshal() error")
+
+		list, ok := m.Value("list").([]any)
+		if assert.True(t, ok, "array value type") && assert.Len(t, list, 3, "array length") {
+			inner, ok := list[0].(*Map[string, any])
+			if assert.True(t, ok, "array element type") {
+				assert.Equal(t, []string{"z", "y"}, slices.Collect(inner.Keys()), "array element Keys()")
+			}
+		}
+
+		got, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, data, string(got), "json.Marshal() output")
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		m := New[string, map[string]int]()
+		err := json.Unmarshal([]byte(`{"a":{"z":1,"y":2}}`), m)
+		assert.NoError(t, err, "json.Unmarshal() error")
+		assert.Equal(t, map[string]int{"z": 1, "y": 2}, m.Value("a"), "json.Unmarshal() output")
+	})
+}
+
+func TestUnmarshalKeyError(t *testing.T) {
+	m := New[int8, string]()
+	err := json.Unmarshal([]byte(`{"1":"a","99999":"b"}`), m)
+	assert.ErrorContains(t, err, `parsing key "99999" as int8: `, "json.Unmarshal() error for out of range key")
+
+	n := New[int, string]()
+	err = json.Unmarshal([]byte(`{"1.5":"a"}`), n)
+	assert.ErrorContains(t, err, `parsing key "1.5" as int: `, "json.Unmarshal() error for invalid key")
+
+	err = json.Unmarshal([]byte(`{"null":"a"}`), n)
+	assert.EqualError(t, err, `parsing key "null" as int: null is not a valid key`, "json.Unmarshal() error for null key")
+	assert.Equal(t, 0, n.Len(), "Len() after null key")
+
+	b := NewUnorderedKeyMap[bool, string]()
+	err = json.Unmarshal([]byte(`{" true":"a"}`), b)
+	assert.EqualError(t, err, `parsing key " true" as bool: surrounding whitespace in key`, "json.Unmarshal() error for key with whitespace")
 }
 
-func Test_parseKey(t *testing.T) {
+func TestDecodeJSON(t *testing.T) {
+	t.Run("object", func(t *testing.T) {
+		r := strings.NewReader(`{"b":2,"a":1,"c":3}`)
+		m, err := DecodeJSON[string, int](r)
+		assert.NoError(t, err, "DecodeJSON() error")
+		assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()), "Keys() after DecodeJSON()")
+		assert.Equal(t, []int{2, 1, 3}, slices.Collect(m.Values()), "Values() after DecodeJSON()")
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		for _, data := range []string{`[1,2]`, `"a"`, `null`} {
+			_, err := DecodeJSON[string, int](strings.NewReader(data))
+			assert.Error(t, err, "DecodeJSON(%s) error", data)
+		}
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		_, err := DecodeJSON[int, int](strings.NewReader(`{"a":1}`))
+		assert.Error(t, err, "DecodeJSON() error")
+	})
+}
+
+func TestEncodeJSON(t *testing.T) {
+	t.Run("pipe", func(t *testing.T) {
+		m := New[string, any]()
+		for i := range 1000 {
+			m.Set(strconv.Itoa(i), []int{i, i * 2})
+		}
+		want, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal() error")
+
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(m.EncodeJSON(pw))
+		}()
+		got, err := io.ReadAll(pr)
+		assert.NoError(t, err, "EncodeJSON() error")
+		assert.Equal(t, string(want), string(got), "EncodeJSON() output")
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		var m *Map[string, int]
+		var sb strings.Builder
+		assert.NoError(t, m.EncodeJSON(&sb), "EncodeJSON() error")
+		assert.Equal(t, "null", sb.String(), "EncodeJSON() output")
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		m := New(Entry[string, int]{"a", 1})
+		pr, pw := io.Pipe()
+		errClosed := errors.New("closed")
+		pr.CloseWithError(errClosed)
+		assert.ErrorIs(t, m.EncodeJSON(pw), errClosed, "EncodeJSON() error")
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		m := New(Entry[string, any]{"a", make(chan int)})
+		assert.Error(t, m.EncodeJSON(io.Discard), "EncodeJSON() error")
+	})
+}
+
+func TestMarshalFloatKeys(t *testing.T) {
+	t.Run("non-finite", func(t *testing.T) {
+		for _, key := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			_, err := json.Marshal(New(Entry[float64, int]{key, 1}))
+			assert.ErrorContains(t, err, "unsupported key value", "json.Marshal() error with key %v", key)
+		}
+	})
+
+	t.Run("negative zero", func(t *testing.T) {
+		data, err := json.Marshal(New(Entry[float64, int]{math.Copysign(0, -1), 1}))
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, `{"-0":1}`, string(data), "json.Marshal() output")
+
+		m := New[float64, int]()
+		assert.NoError(t, json.Unmarshal(data, m), "json.Unmarshal() error")
+		key, _ := m.First()
+		assert.True(t, math.Signbit(key.Key), "First() key is negative zero")
+	})
+
+	t.Run("non-finite strings", func(t *testing.T) {
+		m := New[float64, int]()
+		assert.Error(t, json.Unmarshal([]byte(`{"NaN":1}`), m), "json.Unmarshal() error")
+		assert.Error(t, json.Unmarshal([]byte(`{"+Inf":1}`), m), "json.Unmarshal() error")
+	})
+}
+
+func TestMarshalHTMLEscaping(t *testing.T) {
+	m := New[string, any](
+		Entry[string, any]{"a&b", "<script>alert(1)</script>"},
+		Entry[string, any]{"nested", New(Entry[string, string]{"<k>", "x > y"})},
+	)
+
+	t.Run("default", func(t *testing.T) {
+		got, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, `{"a\u0026b":"\u003cscript\u003ealert(1)\u003c/script\u003e","nested":{"\u003ck\u003e":"x \u003e y"}}`, string(got), "json.Marshal() output")
+
+		// Matches a regular map with the same entries
+		want, err := json.Marshal(map[string]string{"a&b": "<script>alert(1)</script>"})
+		assert.NoError(t, err, "json.Marshal() error")
+		got, err = json.Marshal(New(Entry[string, string]{"a&b", "<script>alert(1)</script>"}))
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, string(want), string(got), "json.Marshal() output compared to map")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		got, err := m.MarshalJSONOptions(EncodeOptions{DisableHTMLEscaping: true})
+		assert.NoError(t, err, "MarshalJSONOptions() error")
+		assert.Equal(t, `{"a&b":"<script>alert(1)</script>","nested":{"<k>":"x > y"}}`, string(got), "MarshalJSONOptions() output")
+	})
+
+	t.Run("disabled matches encoding/json", func(t *testing.T) {
+		for _, key := range []string{`\u003c<`, `\\u0026&`, "<\n>\u2028\x00", `a"b`, "plain"} {
+			got, err := New(E(key, 1)).MarshalJSONOptions(EncodeOptions{DisableHTMLEscaping: true})
+			assert.NoError(t, err, "MarshalJSONOptions() error")
+
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			assert.NoError(t, enc.Encode(map[string]int{key: 1}), "Encode() error")
+			assert.Equal(t, strings.TrimSuffix(buf.String(), "\n"), string(got), "MarshalJSONOptions(%q) output", key)
+		}
+	})
+
+	t.Run("disabled allocations", func(t *testing.T) {
+		m := New[string, int]()
+		for i := range 100 {
+			m.Set(fmt.Sprintf("<%d>", i), i)
+		}
+		escaped := testing.AllocsPerRun(10, func() {
+			m.MarshalJSONOptions(EncodeOptions{})
+		})
+		unescaped := testing.AllocsPerRun(10, func() {
+			m.MarshalJSONOptions(EncodeOptions{DisableHTMLEscaping: true})
+		})
+		// Keys are unescaped without allocating, with a few
+		// allocations allowed for states dropped by the pool
+		assert.LessOrEqual(t, unescaped, escaped+10, "allocations per MarshalJSONOptions() without HTML escaping")
+	})
+}
+
+func TestMarshalCycle(t *testing.T) {
+	t.Run("direct", func(t *testing.T) {
+		m := New(E[string, any]("a", 1))
+		m.Set("self", m)
+		_, err := m.MarshalJSON()
+		assert.EqualError(t, err, "json: unsupported value: encountered a cycle", "MarshalJSON() error")
+
+		var sb strings.Builder
+		assert.EqualError(t, m.EncodeJSON(&sb), "json: unsupported value: encountered a cycle", "EncodeJSON() error")
+	})
+
+	t.Run("through arrays", func(t *testing.T) {
+		m := New[string, any]()
+		inner := New[string, any]()
+		m.Set("list", []any{inner})
+		inner.Set("parent", m)
+		_, err := json.Marshal(m)
+		var unsupported *json.UnsupportedValueError
+		assert.ErrorAs(t, err, &unsupported, "json.Marshal() error")
+		assert.ErrorContains(t, err, "encountered a cycle", "json.Marshal() error")
+
+		list := []any{1, nil}
+		list[1] = list
+		_, err = New(E[string, any]("list", list)).MarshalJSON()
+		assert.EqualError(t, err, "json: unsupported value: encountered a cycle", "MarshalJSON() error with array containing itself")
+	})
+
+	t.Run("deep without cycle", func(t *testing.T) {
+		m := New[string, any]()
+		inner := m
+		for range startDetectingCyclesAfter * 2 {
+			next := New[string, any]()
+			inner.Set("a", []any{next})
+			inner = next
+		}
+		_, err := m.MarshalJSON()
+		assert.NoError(t, err, "MarshalJSON() error")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		// Block every encoding of the shared map until all are in progress
+		var started sync.WaitGroup
+		release := make(chan struct{})
+		n := startDetectingCyclesAfter + 100
+		started.Add(n)
+		m := New(E[string, any]("a", blockingMarshaler{started: &started, release: release}))
+
+		errs := make(chan error, n)
+		for range n {
+			go func() {
+				_, err := json.Marshal(m)
+				errs <- err
+			}()
+		}
+		started.Wait()
+		close(release)
+		for range n {
+			assert.NoError(t, <-errs, "json.Marshal() error")
+		}
+	})
+
+	t.Run("repeated without cycle", func(t *testing.T) {
+		shared := New(E("x", 1))
+		m := New(E[string, any]("a", shared), E[string, any]("b", []any{shared, shared}))
+		data, err := m.MarshalJSON()
+		assert.NoError(t, err, "MarshalJSON() error")
+		assert.Equal(t, `{"a":{"x":1},"b":[{"x":1},{"x":1}]}`, string(data), "MarshalJSON() output")
+	})
+}
+
+// blockingMarshaler marshals as null once release is closed.
+type blockingMarshaler struct {
+	started *sync.WaitGroup
+	release chan struct{}
+}
+
+func (b blockingMarshaler) MarshalJSON() ([]byte, error) {
+	b.started.Done()
+	<-b.release
+	return []byte(`null`), nil
+}
+
+func TestMarshalJSONIndent(t *testing.T) {
+	m := New[string, any](
+		Entry[string, any]{"name", "omap"},
+		Entry[string, any]{"nested", New(
+			Entry[string, int]{"z", 1},
+			Entry[string, int]{"a", 2},
+		)},
+		Entry[string, any]{"list", []int{1, 2}},
+		Entry[string, any]{"empty", New[string, int]()},
+	)
+	want := `{
+  "name": "omap",
+  "nested": {
+    "z": 1,
+    "a": 2
+  },
+  "list": [
+    1,
+    2
+  ],
+  "empty": {}
+}`
+	got, err := m.MarshalJSONIndent("", "  ")
+	assert.NoError(t, err, "MarshalJSONIndent() error")
+	assert.Equal(t, want, string(got), "MarshalJSONIndent() output")
+
+	var nilMap *Map[string, int]
+	got, err = nilMap.MarshalJSONIndent("", "  ")
+	assert.NoError(t, err, "MarshalJSONIndent() error on nil map")
+	assert.Equal(t, "null", string(got), "MarshalJSONIndent() output on nil map")
+}
+
+func TestUnmarshalJSONOptions(t *testing.T) {
+	t.Run("duplicate keys", func(t *testing.T) {
+		data := []byte(`{"a":1,"b":2,"a":3}`)
+		tests := []struct {
+			name       string
+			policy     DuplicateKeyPolicy
+			wantKeys   []string
+			wantValues []int
+			wantErr    string
+		}{
+			{
+				name:       "overwrite",
+				policy:     DuplicateKeysOverwrite,
+				wantKeys:   []string{"a", "b"},
+				wantValues: []int{3, 2},
+			},
+			{
+				name:       "move to end",
+				policy:     DuplicateKeysMoveToEnd,
+				wantKeys:   []string{"b", "a"},
+				wantValues: []int{2, 3},
+			},
+			{
+				name:    "error",
+				policy:  DuplicateKeysError,
+				wantErr: `duplicate key "a"`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := New[string, int]()
+				err := m.UnmarshalJSONOptions(data, DecodeOptions{DuplicateKeys: tt.policy})
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr, "UnmarshalJSONOptions() error")
+					return
+				}
+				assert.NoError(t, err, "UnmarshalJSONOptions() error")
+				assert.Equal(t, tt.wantKeys, slices.Collect(m.Keys()), "Keys() after UnmarshalJSONOptions()")
+				assert.Equal(t, tt.wantValues, slices.Collect(m.Values()), "Values() after UnmarshalJSONOptions()")
+			})
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		m := New[string, int]()
+		assert.NoError(t, json.Unmarshal([]byte(`{"a":1,"a":2}`), m), "json.Unmarshal() error")
+		assert.Equal(t, []Entry[string, int]{{"a", 2}}, m.Entries(), "Entries() after json.Unmarshal()")
+	})
+
+	t.Run("use number", func(t *testing.T) {
+		data := []byte(`{"id":1234567890123456789,"nested":{"n":[9007199254740993]}}`)
+		m := New[string, any]()
+		assert.NoError(t, m.UnmarshalJSONOptions(data, DecodeOptions{UseNumber: true}), "UnmarshalJSONOptions() error")
+		assert.Equal(t, json.Number("1234567890123456789"), m.Value("id"), "Value() after UnmarshalJSONOptions()")
+
+		got, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, string(data), string(got), "json.Marshal() after UnmarshalJSONOptions()")
+
+		m = New[string, any]()
+		assert.NoError(t, m.UnmarshalJSONOptions(data, DecodeOptions{}), "UnmarshalJSONOptions() error")
+		assert.Equal(t, float64(1234567890123456789), m.Value("id"), "Value() without UseNumber")
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		// nested returns n levels of objects, with an array at the innermost level
+		nested := func(n int) []byte {
+			return []byte(strings.Repeat(`{"a":`, n-1) + "[1]" + strings.Repeat("}", n-1))
+		}
+		tests := []struct {
+			name     string
+			data     []byte
+			maxDepth int
+			wantErr  bool
+		}{
+			{name: "under limit", data: nested(3), maxDepth: 4},
+			{name: "at limit", data: nested(4), maxDepth: 4},
+			{name: "over limit", data: nested(5), maxDepth: 4, wantErr: true},
+			{name: "default at limit", data: nested(DefaultMaxDepth)},
+			{name: "default over limit", data: nested(DefaultMaxDepth + 1), wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := New[string, any]()
+				err := m.UnmarshalJSONOptions(tt.data, DecodeOptions{MaxDepth: tt.maxDepth})
+				if tt.wantErr {
+					assert.EqualError(t, err, "maximum nesting depth exceeded", "UnmarshalJSONOptions() error")
+					return
+				}
+				assert.NoError(t, err, "UnmarshalJSONOptions() error")
+			})
+		}
+	})
+
+	t.Run("nested duplicate keys", func(t *testing.T) {
+		m := New[string, any]()
+		err := m.UnmarshalJSONOptions([]byte(`{"x":{"a":1,"a":2}}`), DecodeOptions{DuplicateKeys: DuplicateKeysError})
+		assert.ErrorContains(t, err, `duplicate key "a"`, "UnmarshalJSONOptions() error")
+	})
+}
+
+// dateKey is a date stored as "YYYYMMDD", which is marshaled
+// as text in the form "YYYY-MM-DD".
+type dateKey string
+
+func (d dateKey) MarshalText() ([]byte, error) {
+	if len(d) != 8 {
+		return nil, fmt.Errorf("invalid date %q", string(d))
+	}
+	return []byte(string(d[:4]) + "-" + string(d[4:6]) + "-" + string(d[6:])), nil
+}
+
+func (d *dateKey) UnmarshalText(text []byte) error {
+	year, rest, _ := strings.Cut(string(text), "-")
+	month, day, _ := strings.Cut(rest, "-")
+	if len(year) != 4 || len(month) != 2 || len(day) != 2 {
+		return fmt.Errorf("invalid date %q", string(text))
+	}
+	*d = dateKey(year + month + day)
+	return nil
+}
+
+func TestTextMarshalerKeys(t *testing.T) {
+	m := New([]Entry[dateKey, int]{
+		{"20240301", 3},
+		{"20240115", 1},
+	}...)
+	data, err := json.Marshal(m)
+	assert.NoError(t, err, "json.Marshal() error")
+	assert.Equal(t, `{"2024-03-01":3,"2024-01-15":1}`, string(data), "json.Marshal() output")
+
+	got := New[dateKey, int]()
+	assert.NoError(t, json.Unmarshal(data, got), "json.Unmarshal() error")
+	assert.Equal(t, m, got, "json.Unmarshal() output")
+
+	err = json.Unmarshal([]byte(`{"20240301":3}`), got)
+	assert.ErrorContains(t, err, `invalid date "20240301"`, "json.Unmarshal() error for invalid key")
+
+	_, err = json.Marshal(New(Entry[dateKey, int]{"2024", 1}))
+	assert.ErrorContains(t, err, `invalid date "2024"`, "json.Marshal() error for invalid key")
+}
+
+func TestMarshal(t *testing.T) {
+	populated := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+	}...)
+
 	tests := []struct {
-		name      string
-		keyString string
-		key       any
-		wantKey   any
-		wantErr   bool
+		name           string
+		m              *Map[string, int]
+		want           string
+		wantObject     string
+		wantOmitEmpty  string
+		wantWithoutTag string
 	}{
 		{
-			name:      "int",
-			keyString: "100",
-			key:       new(int),
-			wantKey:   ptrTo(int(100)),
-			wantErr:   false,
-		},
-		{
-			name:      "int8",
-			keyString: "100",
-			key:       new(int8),
-			wantKey:   ptrTo(int8(100)),
-			wantErr:   false,
+			name:           "nil",
+			m:              nil,
+			want:           `null`,
+			wantObject:     `{}`,
+			wantOmitEmpty:  `{}`,
+			wantWithoutTag: `{"M":null}`,
 		},
 		{
-			name:      "int8 overflow",
-			keyString: "100000000",
-			key:       new(int8),
-			wantKey:   ptrTo(int8(0)),
-			wantErr:   true,
+			name:           "empty",
+			m:              New[string, int](),
+			want:           `{}`,
+			wantObject:     `{}`,
+			wantOmitEmpty:  `{"m":{}}`,
+			wantWithoutTag: `{"M":{}}`,
 		},
 		{
-			name:      "float64",
-			keyString: "100.05",
-			key:       new(float64),
-			wantKey:   ptrTo(float64(100.05)),
-			wantErr:   false,
-		},
-		{
-			name:      "string",
-			keyString: "[1,2,3]",
-			key:       new(string),
-			wantKey:   nil,
-			wantErr:   false,
-		},
-		{
-			name:      "string double pointer",
-			keyString: "[1,2,3]",
-			key:       new(*string),
-			wantKey:   new(*string),
-			wantErr:   true,
-		},
-		{
-			name:      "non pointer",
-			keyString: "100",
-			key:       int(0),
-			wantKey:   int(0),
-			wantErr:   true,
-		},
-		{
-			name:      "int wrapper",
-			keyString: "100",
-			key:       new(intWrapper),
-			wantKey:   ptrTo(intWrapper(100)),
-			wantErr:   false,
-		},
-		{
-			name:      "string wrapper",
-			keyString: "100",
-			key:       new(stringWrapper),
-			wantKey:   ptrTo(stringWrapper("100")),
-			wantErr:   false,
+			name:           "populated",
+			m:              populated,
+			want:           `{"b":2,"a":1}`,
+			wantObject:     `{"b":2,"a":1}`,
+			wantOmitEmpty:  `{"m":{"b":2,"a":1}}`,
+			wantWithoutTag: `{"M":{"b":2,"a":1}}`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := parseKey(tt.keyString, tt.key)
-			if tt.wantErr {
-				assert.Error(t, err, "parseKey() error")
-			} else {
-				assert.NoError(t, err, "parseKey() error")
-			}
-			assert.Equal(t, tt.wantKey, tt.key, "parseKey() output")
+			got, err := tt.m.MarshalJSON()
+			assert.NoError(t, err, "MarshalJSON() error")
+			assert.Equal(t, tt.want, string(got), "MarshalJSON() output")
+
+			got, err = tt.m.MarshalJSONObject()
+			assert.NoError(t, err, "MarshalJSONObject() error")
+			assert.Equal(t, tt.wantObject, string(got), "MarshalJSONObject() output")
+
+			got, err = json.Marshal(struct {
+				M *Map[string, int] `json:"m,omitempty"`
+			}{tt.m})
+			assert.NoError(t, err, "json.Marshal() error")
+			assert.Equal(t, tt.wantOmitEmpty, string(got), "json.Marshal() output with omitempty")
+
+			got, err = json.Marshal(struct {
+				M *Map[string, int]
+			}{tt.m})
+			assert.NoError(t, err, "json.Marshal() error")
+			assert.Equal(t, tt.wantWithoutTag, string(got), "json.Marshal() output without tag")
 		})
 	}
 }
 
-type intWrapper int
-type stringWrapper string
+func TestText(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...)
+		text, err := m.MarshalText()
+		assert.NoError(t, err, "MarshalText() error")
+		assert.Equal(t, `{"b":2,"a":1}`, string(text), "MarshalText() output")
+
+		got := New[string, int]()
+		err = got.UnmarshalText(text)
+		assert.NoError(t, err, "UnmarshalText() error")
+		assert.Equal(t, m, got, "UnmarshalText() output")
+	})
+
+	t.Run("null and empty", func(t *testing.T) {
+		var nilMap *Map[string, int]
+		text, err := nilMap.MarshalText()
+		assert.NoError(t, err, "MarshalText() error")
+		assert.Equal(t, `null`, string(text), "MarshalText() output for nil map")
+
+		text, err = New[string, int]().MarshalText()
+		assert.NoError(t, err, "MarshalText() error")
+		assert.Equal(t, `{}`, string(text), "MarshalText() output for empty map")
+
+		m := New(Entry[string, int]{"x", 1})
+		assert.NoError(t, m.UnmarshalText([]byte(`null`)), "UnmarshalText() error")
+		assert.Equal(t, New(Entry[string, int]{"x", 1}), m, "UnmarshalText() output for null")
+		assert.NoError(t, m.UnmarshalText([]byte(`{}`)), "UnmarshalText() error")
+		assert.Equal(t, 0, m.Len(), "Len() after UnmarshalText() for empty object")
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		type config struct {
+			Name     string            `xml:"name,attr"`
+			Settings *Map[string, int] `xml:"settings"`
+		}
+		want := config{
+			Name: "test",
+			Settings: New([]Entry[string, int]{
+				{"z", 26},
+				{"a", 1},
+			}...),
+		}
+		data, err := xml.Marshal(want)
+		assert.NoError(t, err, "xml.Marshal() error")
+
+		var got config
+		err = xml.Unmarshal(data, &got)
+		assert.NoError(t, err, "xml.Unmarshal() error")
+		assert.Equal(t, want, got, "xml.Unmarshal() output")
+	})
+}
+
+func TestMarshalJSONReusesBuffers(t *testing.T) {
+	a := New(Entry[string, string]{"a", "first"})
+	b := New(Entry[string, string]{"b", "second"})
+	gotA, err := a.MarshalJSON()
+	assert.NoError(t, err, "MarshalJSON() error")
+	gotB, err := b.MarshalJSON()
+	assert.NoError(t, err, "MarshalJSON() error")
+	assert.Equal(t, `{"a":"first"}`, string(gotA), "first MarshalJSON() output after second call")
+	assert.Equal(t, `{"b":"second"}`, string(gotB), "second MarshalJSON() output")
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	type config struct {
+		Name    string
+		Entries *Map[string, int]
+	}
+	c := config{Name: "bench", Entries: New[string, int]()}
+	for i := range 1000 {
+		c.Entries.Set("key"+strconv.Itoa(i), i)
+	}
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := json.Marshal(c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
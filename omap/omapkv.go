@@ -0,0 +1,118 @@
+package omap
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/justenstall/omap/omap/internal/jsonkey"
+)
+
+// WriteKeyValue writes the entries of m to w as lines of the form
+// key<sep>value in insertion order, as used by .env and .properties files.
+// Keys are formatted as they would appear unquoted in a JSON object, and
+// values are formatted with [fmt.Sprint].
+//
+// A key or value is written as a double-quoted Go string literal, as by
+// [strconv.Quote], if it contains sep or a line break, or begins with a
+// double quote. Otherwise it is written as-is. WriteKeyValue returns an
+// error if sep is empty.
+func (m *Map[K, V]) WriteKeyValue(w io.Writer, sep string) error {
+	if sep == "" {
+		return errors.New("empty separator")
+	}
+	bw := bufio.NewWriter(w)
+	for key, value := range m.All() {
+		keyString, err := jsonkey.Format(key)
+		if err != nil {
+			return fmt.Errorf("formatting key (type %T): %w", key, err)
+		}
+		bw.WriteString(quoteField(keyString, sep))
+		bw.WriteString(sep)
+		bw.WriteString(quoteField(fmt.Sprint(value), sep))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// ReadKeyValue reads lines of the form key<sep>value from r into a new
+// ordered map in the order they appear, as written by [Map.WriteKeyValue].
+// Keys are parsed as they would be from a JSON object, and values are
+// parsed by calling parse. Blank lines are skipped. A key or value that
+// begins with a double quote is unquoted with [strconv.Unquote]. If several
+// lines have the same key, the last value is kept at the position of the
+// first, as with [Map.Set].
+func ReadKeyValue[K cmp.Ordered, V any](r io.Reader, sep string, parse func(s string) (V, error)) (*Map[K, V], error) {
+	if sep == "" {
+		return nil, errors.New("empty separator")
+	}
+	m := New[K, V]()
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSuffix(s.Text(), "\r")
+		if text == "" {
+			continue
+		}
+
+		keyString, valueString, err := splitKeyValue(text, sep)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		var key K
+		if err := jsonkey.Parse(keyString, &key); err != nil {
+			return nil, fmt.Errorf("line %d: parsing key %q as %T: %w", line, keyString, key, err)
+		}
+		value, err := parse(valueString)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parsing value: %w", line, err)
+		}
+		m.Set(key, value)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// quoteField quotes s if it could not be read back
+// unambiguously from a line separated by sep.
+func quoteField(s, sep string) string {
+	if strings.Contains(s, sep) || strings.ContainsAny(s, "\r\n") || strings.HasPrefix(s, `"`) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// splitKeyValue splits a line into its unquoted key and value.
+func splitKeyValue(line, sep string) (key, value string, err error) {
+	if strings.HasPrefix(line, `"`) {
+		// The quoted key may contain sep, so find its end first
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return "", "", fmt.Errorf("unquoting key: %w", err)
+		}
+		key, _ = strconv.Unquote(quoted)
+		rest, found := strings.CutPrefix(line[len(quoted):], sep)
+		if !found {
+			return "", "", fmt.Errorf("missing separator %q after quoted key", sep)
+		}
+		value = rest
+	} else {
+		var found bool
+		key, value, found = strings.Cut(line, sep)
+		if !found {
+			return "", "", fmt.Errorf("missing separator %q", sep)
+		}
+	}
+	if strings.HasPrefix(value, `"`) {
+		value, err = strconv.Unquote(value)
+		if err != nil {
+			return "", "", fmt.Errorf("unquoting value: %w", err)
+		}
+	}
+	return key, value, nil
+}
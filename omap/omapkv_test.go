@@ -0,0 +1,76 @@
+package omap
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readString(s string) (string, error) {
+	return s, nil
+}
+
+func TestKeyValue(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		m := New([]Entry[string, string]{
+			{"HOST", "localhost"},
+			{"DSN", "user=admin password=x"},
+			{"a=b", "c"},
+			{"QUOTED", `"value"`},
+			{"MULTILINE", "one\ntwo"},
+			{"EMPTY", ""},
+		}...)
+		var sb strings.Builder
+		assert.NoError(t, m.WriteKeyValue(&sb, "="), "WriteKeyValue() error")
+		want := `HOST=localhost
+DSN="user=admin password=x"
+"a=b"=c
+QUOTED="\"value\""
+MULTILINE="one\ntwo"
+EMPTY=
+`
+		assert.Equal(t, want, sb.String(), "WriteKeyValue() output")
+
+		got, err := ReadKeyValue[string](strings.NewReader(sb.String()), "=", readString)
+		assert.NoError(t, err, "ReadKeyValue() error")
+		assert.Equal(t, m, got, "ReadKeyValue() output")
+	})
+
+	t.Run("other separator", func(t *testing.T) {
+		m := New([]Entry[int, int]{
+			{3, 30},
+			{1, 10},
+		}...)
+		var sb strings.Builder
+		assert.NoError(t, m.WriteKeyValue(&sb, ": "), "WriteKeyValue() error")
+		assert.Equal(t, "3: 30\n1: 10\n", sb.String(), "WriteKeyValue() output")
+
+		got, err := ReadKeyValue[int](strings.NewReader("3: 30\n\n1: 10\r\n"), ": ", strconv.Atoi)
+		assert.NoError(t, err, "ReadKeyValue() error")
+		assert.Equal(t, m, got, "ReadKeyValue() output")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			data    string
+			wantErr string
+		}{
+			{name: "missing separator", data: "a=1\nb\n", wantErr: `line 2: missing separator "="`},
+			{name: "unterminated key", data: `"a=1`, wantErr: "line 1: unquoting key: invalid syntax"},
+			{name: "missing separator after key", data: `"a"1`, wantErr: `line 1: missing separator "=" after quoted key`},
+			{name: "invalid quoted value", data: `a="1`, wantErr: "line 1: unquoting value: invalid syntax"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := ReadKeyValue[string](strings.NewReader(tt.data), "=", readString)
+				assert.EqualError(t, err, tt.wantErr, "ReadKeyValue() error")
+			})
+		}
+
+		var sb strings.Builder
+		assert.Error(t, New[string, int]().WriteKeyValue(&sb, ""), "WriteKeyValue() with empty separator")
+	})
+}
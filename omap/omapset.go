@@ -0,0 +1,36 @@
+package omap
+
+import "cmp"
+
+// NewSet creates an ordered set, which is an ordered map with empty struct
+// values, containing keys in the order given. Duplicate keys keep their
+// first position. Sets can be combined with [Union], [Intersect], and
+// [Difference].
+func NewSet[K cmp.Ordered](keys ...K) *Map[K, struct{}] {
+	s := NewWithCapacity[K, struct{}](len(keys))
+	for _, key := range keys {
+		AddKey(s, key)
+	}
+	return s
+}
+
+// AddKey adds key to the back of the ordered set s and reports whether it
+// was added. If the key already exists, its position is preserved and
+// AddKey returns false. AddKey panics if s is nil.
+func AddKey[K cmp.Ordered](s *Map[K, struct{}], key K) bool {
+	if s == nil {
+		panic("omap: AddKey called on nil *Map")
+	}
+	if _, ok := s.entries[key]; ok {
+		return false
+	}
+	s.push(key, struct{}{})
+	return true
+}
+
+// RemoveKey removes key from the ordered set s and reports whether it was
+// present. If s is nil, RemoveKey does nothing and returns false.
+func RemoveKey[K cmp.Ordered](s *Map[K, struct{}], key K) bool {
+	_, ok := s.Pop(key)
+	return ok
+}
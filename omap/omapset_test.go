@@ -0,0 +1,58 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedSet(t *testing.T) {
+	t.Run("add and remove", func(t *testing.T) {
+		s := NewSet("c", "a", "c", "b")
+		assert.Equal(t, []string{"c", "a", "b"}, slices.Collect(s.Keys()), "Keys() after NewSet()")
+
+		assert.True(t, AddKey(s, "d"), "AddKey() new key")
+		assert.False(t, AddKey(s, "a"), "AddKey() existing key")
+		assert.Equal(t, []string{"c", "a", "b", "d"}, slices.Collect(s.Keys()), "Keys() after AddKey()")
+
+		assert.True(t, RemoveKey(s, "a"), "RemoveKey() existing key")
+		assert.False(t, RemoveKey(s, "a"), "RemoveKey() missing key")
+		assert.Equal(t, []string{"c", "b", "d"}, slices.Collect(s.Keys()), "Keys() after RemoveKey()")
+
+		var nilSet *Map[string, struct{}]
+		assert.False(t, RemoveKey(nilSet, "a"), "RemoveKey() on nil set")
+		assert.PanicsWithValue(t, "omap: AddKey called on nil *Map", func() {
+			AddKey(nilSet, "a")
+		}, "AddKey() on nil set")
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		a := NewSet("d", "b", "a", "c")
+		b := NewSet("e", "c", "f", "b")
+		tests := []struct {
+			name string
+			op   func(a, b *Map[string, struct{}]) *Map[string, struct{}]
+			a, b *Map[string, struct{}]
+			want []string
+		}{
+			{name: "union", op: Union[string, struct{}], a: a, b: b, want: []string{"d", "b", "a", "c", "e", "f"}},
+			{name: "union reversed", op: Union[string, struct{}], a: b, b: a, want: []string{"e", "c", "f", "b", "d", "a"}},
+			{name: "intersect", op: Intersect[string, struct{}], a: a, b: b, want: []string{"b", "c"}},
+			{name: "intersect reversed", op: Intersect[string, struct{}], a: b, b: a, want: []string{"c", "b"}},
+			{name: "difference", op: Difference[string, struct{}], a: a, b: b, want: []string{"d", "a"}},
+			{name: "difference reversed", op: Difference[string, struct{}], a: b, b: a, want: []string{"e", "f"}},
+			{name: "union with nil", op: Union[string, struct{}], a: nil, b: b, want: []string{"e", "c", "f", "b"}},
+			{name: "intersect with nil", op: Intersect[string, struct{}], a: a, b: nil, want: nil},
+			{name: "difference with nil", op: Difference[string, struct{}], a: a, b: nil, want: []string{"d", "b", "a", "c"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := tt.op(tt.a, tt.b)
+				assert.Equal(t, tt.want, slices.Collect(got.Keys()), "Keys()")
+			})
+		}
+		assert.Equal(t, []string{"d", "b", "a", "c"}, slices.Collect(a.Keys()), "a Keys() after operations")
+		assert.Equal(t, []string{"e", "c", "f", "b"}, slices.Collect(b.Keys()), "b Keys() after operations")
+	})
+}
@@ -0,0 +1,54 @@
+package omap
+
+import (
+	"cmp"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+var (
+	_ driver.Valuer = SQLMap[string, any]{}
+	_ sql.Scanner   = (*SQLMap[string, any])(nil)
+)
+
+// SQLMap wraps an ordered map so it can be stored as a JSON object in a
+// database column, such as a PostgreSQL jsonb column. It implements
+// [driver.Valuer] and [sql.Scanner], which Map cannot implement itself
+// because [Map.Value] already looks up values by key.
+//
+// A nil Map is stored as SQL NULL.
+type SQLMap[K cmp.Ordered, V any] struct {
+	Map *Map[K, V]
+}
+
+// Value implements [driver.Valuer].
+// The map is encoded as JSON using [Map.MarshalJSON].
+func (s SQLMap[K, V]) Value() (driver.Value, error) {
+	if s.Map == nil {
+		return nil, nil
+	}
+	return s.Map.MarshalJSON()
+}
+
+// Scan implements [sql.Scanner].
+// The source must be a JSON object as []byte or string, or nil.
+// Scanning nil sets Map to nil.
+func (s *SQLMap[K, V]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		s.Map = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("cannot scan type %T into %T", src, s)
+	}
+	if s.Map == nil {
+		s.Map = New[K, V]()
+	}
+	return s.Map.UnmarshalJSON(data)
+}
@@ -0,0 +1,84 @@
+package omap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLMapValue(t *testing.T) {
+	s := SQLMap[string, int]{
+		Map: New([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...),
+	}
+	got, err := s.Value()
+	assert.NoError(t, err, "Value() error")
+	assert.Equal(t, []byte(`{"b":2,"a":1}`), got, "Value() output")
+
+	got, err = SQLMap[string, int]{}.Value()
+	assert.NoError(t, err, "Value() error")
+	assert.Nil(t, got, "Value() output for nil map")
+}
+
+func TestSQLMapScan(t *testing.T) {
+	want := New([]Entry[string, int]{
+		{"b", 2},
+		{"a", 1},
+	}...)
+
+	tests := []struct {
+		name    string
+		src     any
+		want    *Map[string, int]
+		wantErr string
+	}{
+		{
+			name: "nil",
+			src:  nil,
+			want: nil,
+		},
+		{
+			name: "bytes",
+			src:  []byte(`{"b":2,"a":1}`),
+			want: want,
+		},
+		{
+			name: "string",
+			src:  `{"b":2,"a":1}`,
+			want: want,
+		},
+		{
+			name:    "unsupported type",
+			src:     100,
+			want:    New(Entry[string, int]{"x", 1}),
+			wantErr: "cannot scan type int",
+		},
+		{
+			name:    "invalid JSON",
+			src:     `[1,2]`,
+			want:    New(Entry[string, int]{"x", 1}),
+			wantErr: "cannot parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := SQLMap[string, int]{Map: New(Entry[string, int]{"x", 1})}
+			err := s.Scan(tt.src)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr, "Scan() error")
+			} else {
+				assert.NoError(t, err, "Scan() error")
+			}
+			assert.Equal(t, tt.want, s.Map, "Scan() output")
+		})
+	}
+
+	t.Run("allocates map", func(t *testing.T) {
+		var s SQLMap[string, int]
+		err := s.Scan(`{"b":2,"a":1}`)
+		assert.NoError(t, err, "Scan() error")
+		assert.Equal(t, want, s.Map, "Scan() output")
+	})
+}
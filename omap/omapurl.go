@@ -0,0 +1,51 @@
+package omap
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// URLValues returns the entries of m as [url.Values], with each key
+// mapped to a single value. Since url.Values is a regular map, the
+// order of m is lost; use [EncodeQuery] to keep it in a query string.
+func URLValues(m *Map[string, string]) url.Values {
+	if m == nil {
+		return nil
+	}
+	values := make(url.Values, m.Len())
+	for key, value := range m.All() {
+		values[key] = []string{value}
+	}
+	return values
+}
+
+// FromURLValues creates an ordered map from values.
+// Since url.Values is a regular map, the entries are ordered by
+// sorting the keys. The value slices are copied.
+func FromURLValues(values url.Values) *Map[string, []string] {
+	m := FromMap(values)
+	for _, e := range m.order {
+		e.value = slices.Clone(e.value)
+	}
+	return m
+}
+
+// EncodeQuery encodes m in URL-encoded form ("bar=baz&foo=quux"), like
+// [url.Values.Encode], but in insertion order rather than sorted by key.
+// A key with several values is repeated once for each, in order.
+func EncodeQuery(m *Map[string, []string]) string {
+	var sb strings.Builder
+	for key, values := range m.All() {
+		keyEscaped := url.QueryEscape(key)
+		for _, value := range values {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(keyEscaped)
+			sb.WriteByte('=')
+			sb.WriteString(url.QueryEscape(value))
+		}
+	}
+	return sb.String()
+}
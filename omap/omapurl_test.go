@@ -0,0 +1,42 @@
+package omap
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLValues(t *testing.T) {
+	m := New([]Entry[string, string]{
+		{"z", "last letter"},
+		{"a", "1&2"},
+	}...)
+	assert.Equal(t, url.Values{"z": {"last letter"}, "a": {"1&2"}}, URLValues(m), "URLValues()")
+	assert.Nil(t, URLValues(nil), "URLValues() on nil map")
+
+	values := url.Values{"b": {"2", "3"}, "a": {"1"}}
+	got := FromURLValues(values)
+	assert.Equal(t, []Entry[string, []string]{{"a", []string{"1"}}, {"b", []string{"2", "3"}}}, got.Entries(), "FromURLValues()")
+	got.Value("b")[0] = "20"
+	assert.Equal(t, "2", values.Get("b"), "url.Values after modifying FromURLValues() result")
+}
+
+func TestEncodeQuery(t *testing.T) {
+	m := New([]Entry[string, []string]{
+		{"signature", []string{"abc"}},
+		{"timestamp", []string{"100"}},
+		{"tag", []string{"a b", "c&d"}},
+		{"empty", nil},
+		{"action", []string{"get"}},
+	}...)
+	assert.Equal(t, "signature=abc&timestamp=100&tag=a+b&tag=c%26d&action=get", EncodeQuery(m), "EncodeQuery()")
+
+	sorted := url.Values{}
+	for key, values := range m.All() {
+		sorted[key] = values
+	}
+	assert.Equal(t, "action=get&signature=abc&tag=a+b&tag=c%26d&timestamp=100", sorted.Encode(), "url.Values.Encode()")
+
+	assert.Equal(t, "", EncodeQuery(nil), "EncodeQuery() on nil map")
+}
@@ -0,0 +1,337 @@
+// Package omapyaml encodes ordered maps as YAML mappings that keep their
+// insertion order, using gopkg.in/yaml.v3. It is separate from package
+// omap so that programs that do not use YAML do not link it.
+//
+// Keys are formatted and parsed as they would be in JSON by package omap.
+// Ordered maps nested as values, directly or within []any values, are
+// also encoded in order. When decoding into values of type any, mappings
+// are decoded as *omap.Map[string, any] to preserve their order.
+package omapyaml
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/justenstall/omap/omap"
+	"github.com/justenstall/omap/omap/internal/anymap"
+	"github.com/justenstall/omap/omap/internal/jsonkey"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ yaml.Marshaler   = Map[string, any]{}
+	_ yaml.Unmarshaler = (*Map[string, any])(nil)
+)
+
+// Map wraps an ordered map to implement [yaml.Marshaler] and
+// [yaml.Unmarshaler], such as for use as a struct field.
+type Map[K cmp.Ordered, V any] struct {
+	*omap.Map[K, V]
+}
+
+// MarshalYAML implements [yaml.Marshaler].
+// The map is marshaled as a YAML mapping in insertion order.
+// A nil map is marshaled as null.
+func (m Map[K, V]) MarshalYAML() (any, error) {
+	if m.Map == nil {
+		return nil, nil
+	}
+	return encodeMap(m.Map.AnyMap(anymap.Token{}))
+}
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+// Any existing entries in the map are replaced by the decoded mapping,
+// and a new map is allocated if it is nil. Decoding null leaves the
+// map unchanged.
+func (m *Map[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	if isNull(resolve(node)) {
+		return nil
+	}
+	if m.Map == nil {
+		m.Map = omap.New[K, V]()
+	}
+	return newDecoder().decodeMap(node, m.Map.AnyMap(anymap.Token{}))
+}
+
+// Marshal marshals m as a YAML mapping in insertion order.
+func Marshal[K cmp.Ordered, V any](m *omap.Map[K, V]) ([]byte, error) {
+	return yaml.Marshal(Map[K, V]{m})
+}
+
+// Unmarshal decodes a YAML mapping into a new ordered map.
+func Unmarshal[K cmp.Ordered, V any](data []byte) (*omap.Map[K, V], error) {
+	m := Map[K, V]{omap.New[K, V]()}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m.Map, nil
+}
+
+// orderedMap is implemented by every *omap.Map.
+type orderedMap interface {
+	AnyMap(anymap.Token) anymap.Map
+}
+
+// orderedMapType is the type of [orderedMap].
+var orderedMapType = reflect.TypeFor[orderedMap]()
+
+// encodeMap encodes m as a mapping node in insertion order.
+func encodeMap(m anymap.Map) (*yaml.Node, error) {
+	node := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+	}
+	for key, value := range m.All() {
+		// Format the key
+		keyString, err := jsonkey.Format(key)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling key (type %s): %w", m.KeyType(), err)
+		}
+		keyNode := &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Value: keyString,
+		}
+		// Force string keys to be quoted if they
+		// would otherwise resolve to another type
+		if m.KeyType().Kind() == reflect.String {
+			keyNode.Tag = "!!str"
+		}
+
+		// Encode the value
+		valueNode, err := encodeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling value (type %T): %w", value, err)
+		}
+
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node, nil
+}
+
+// encodeValue encodes value as a node. Ordered maps are encoded in
+// order, including those nested within []any values. All other values
+// are encoded as they would be by [yaml.Marshal].
+func encodeValue(value any) (*yaml.Node, error) {
+	if om, ok := value.(orderedMap); ok && !reflect.ValueOf(om).IsNil() {
+		return encodeMap(om.AnyMap(anymap.Token{}))
+	}
+	if s, ok := value.([]any); ok && s != nil {
+		node := &yaml.Node{
+			Kind: yaml.SequenceNode,
+			Tag:  "!!seq",
+		}
+		for _, elem := range s {
+			elemNode, err := encodeValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, elemNode)
+		}
+		return node, nil
+	}
+	node := new(yaml.Node)
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// decoder decodes the nodes of a document. Like yaml.v3, it reports an
+// error for an alias within its own anchor's value, and for documents
+// whose aliases expand to far more nodes than they contain.
+type decoder struct {
+	// aliases holds the alias nodes currently being expanded.
+	aliases map[*yaml.Node]bool
+
+	// decodeCount is the number of nodes decoded, and aliasCount
+	// is the number of those decoded while expanding an alias.
+	decodeCount int
+	aliasCount  int
+}
+
+func newDecoder() *decoder {
+	return &decoder{aliases: make(map[*yaml.Node]bool)}
+}
+
+// count records that a node is being decoded. It returns an error
+// if the share of nodes decoded through aliases exceeds the ratio
+// allowed by yaml.v3.
+func (d *decoder) count() error {
+	d.decodeCount++
+	if len(d.aliases) > 0 {
+		d.aliasCount++
+	}
+	if d.aliasCount > 100 && d.decodeCount > 1000 &&
+		float64(d.aliasCount)/float64(d.decodeCount) > allowedAliasRatio(d.decodeCount) {
+		return errors.New("document contains excessive aliasing")
+	}
+	return nil
+}
+
+// enter records that the alias node is being expanded, returning an
+// error if it is already being expanded. It must be paired with a call
+// to leave.
+func (d *decoder) enter(alias *yaml.Node) error {
+	if d.aliases[alias] {
+		return fmt.Errorf("anchor '%s' value contains itself", alias.Value)
+	}
+	d.aliases[alias] = true
+	return nil
+}
+
+// leave records that the alias node has been expanded.
+func (d *decoder) leave(alias *yaml.Node) {
+	delete(d.aliases, alias)
+}
+
+// allowedAliasRatio returns the share of nodes decoded through aliases
+// that yaml.v3 allows after decoding decodeCount nodes. Small documents
+// may be almost entirely aliases, while large ones are allowed 10%.
+func allowedAliasRatio(decodeCount int) float64 {
+	const (
+		low  = 400_000
+		high = 4_000_000
+	)
+	switch {
+	case decodeCount <= low:
+		return 0.99
+	case decodeCount >= high:
+		return 0.10
+	default:
+		return 0.99 - 0.89*float64(decodeCount-low)/float64(high-low)
+	}
+}
+
+// decodeMap replaces the entries of m with those decoded from a mapping node.
+func (d *decoder) decodeMap(node *yaml.Node, m anymap.Map) error {
+	if err := d.count(); err != nil {
+		return err
+	}
+	if node.Kind == yaml.AliasNode {
+		// Decode the node the alias refers to
+		if err := d.enter(node); err != nil {
+			return err
+		}
+		defer d.leave(node)
+		return d.decodeMap(node.Alias, m)
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot parse YAML node (line %d) as mapping", node.Line)
+	}
+
+	keyType, valueType := m.KeyType(), m.ValueType()
+
+	// Discard any existing entries
+	m.Clear()
+
+	// Mapping nodes hold alternating key and value nodes
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		// Parse the key as its native type
+		key := reflect.New(keyType)
+		if err := jsonkey.Parse(keyNode.Value, key.Interface()); err != nil {
+			return fmt.Errorf("parsing key %q as %s: %w", keyNode.Value, keyType, err)
+		}
+		// Decode the value
+		value := reflect.New(valueType)
+		if err := d.decodeValue(valueNode, value); err != nil {
+			return fmt.Errorf("unmarshalling value (type %s): %w", valueType, err)
+		}
+
+		// Set the value in the map
+		m.Set(key.Elem().Interface(), value.Elem().Interface())
+	}
+	return nil
+}
+
+// decodeValue decodes node into the value that ptr points to.
+// Ordered maps are decoded in order, and if the value is an empty
+// interface, mappings are decoded as *omap.Map[string, any].
+func (d *decoder) decodeValue(node *yaml.Node, ptr reflect.Value) error {
+	t := ptr.Type().Elem()
+	switch {
+	case t.Kind() == reflect.Pointer && t.Implements(orderedMapType):
+		if isNull(resolve(node)) {
+			return nil
+		}
+		if ptr.Elem().IsNil() {
+			ptr.Elem().Set(reflect.New(t.Elem()))
+		}
+		return d.decodeMap(node, ptr.Elem().Interface().(orderedMap).AnyMap(anymap.Token{}))
+	case t.Kind() == reflect.Interface && t.NumMethod() == 0:
+		v, err := d.decodeAny(node)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			ptr.Elem().Set(reflect.ValueOf(v))
+		}
+		return nil
+	default:
+		// yaml.v3 limits the aliases within the node itself
+		if err := d.count(); err != nil {
+			return err
+		}
+		return node.Decode(ptr.Interface())
+	}
+}
+
+// decodeAny decodes node. YAML mappings are decoded as *omap.Map[string, any],
+// including mappings nested within sequences and mappings.
+// All other values are decoded as they would be by [yaml.Unmarshal].
+func (d *decoder) decodeAny(node *yaml.Node) (any, error) {
+	if err := d.count(); err != nil {
+		return nil, err
+	}
+	switch node.Kind {
+	case yaml.AliasNode:
+		// Decode the node the alias refers to
+		if err := d.enter(node); err != nil {
+			return nil, err
+		}
+		defer d.leave(node)
+		return d.decodeAny(node.Alias)
+	case yaml.MappingNode:
+		// Decode mappings as ordered maps
+		m := omap.New[string, any]()
+		if err := d.decodeMap(node, m.AnyMap(anymap.Token{})); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		// Decode each sequence element
+		s := make([]any, 0, len(node.Content))
+		for _, elem := range node.Content {
+			v, err := d.decodeAny(elem)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	default:
+		// Scalars
+		var v any
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// resolve returns the node that an alias node refers to,
+// or node itself if it is not an alias.
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// isNull reports whether node is a null scalar.
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
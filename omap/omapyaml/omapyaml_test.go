@@ -0,0 +1,137 @@
+package omapyaml
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justenstall/omap/omap"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAML(t *testing.T) {
+	t.Run("string keys", func(t *testing.T) {
+		m := omap.New(omap.E("z", "last"), omap.E("a", "first"), omap.E("1", "number"))
+		data, err := Marshal(m)
+		assert.NoError(t, err, "Marshal() error")
+		assert.Equal(t, "z: last\na: first\n\"1\": number\n", string(data), "Marshal() output")
+
+		got, err := Unmarshal[string, string](data)
+		assert.NoError(t, err, "Unmarshal() error")
+		assert.Equal(t, m, got, "Unmarshal() output")
+	})
+
+	t.Run("int keys", func(t *testing.T) {
+		m := omap.New(omap.E(3, "c"), omap.E(1, "a"), omap.E(2, "b"))
+		data, err := Marshal(m)
+		assert.NoError(t, err, "Marshal() error")
+		assert.Equal(t, "3: c\n1: a\n2: b\n", string(data), "Marshal() output")
+
+		got, err := Unmarshal[int, string](data)
+		assert.NoError(t, err, "Unmarshal() error")
+		assert.Equal(t, m, got, "Unmarshal() output")
+	})
+
+	t.Run("nested maps", func(t *testing.T) {
+		m := omap.New(
+			omap.E("b", omap.New(omap.E("z", 1), omap.E("y", 2))),
+			omap.E("a", omap.New(omap.E("x", 3))),
+		)
+		data, err := Marshal(m)
+		assert.NoError(t, err, "Marshal() error")
+		assert.Equal(t, "b:\n    z: 1\n    y: 2\na:\n    x: 3\n", string(data), "Marshal() output")
+
+		got, err := Unmarshal[string, *omap.Map[string, int]](data)
+		assert.NoError(t, err, "Unmarshal() error")
+		assert.Equal(t, m, got, "Unmarshal() output")
+	})
+
+	t.Run("nested any", func(t *testing.T) {
+		data := "a:\n    z: 1\n    x:\n        - c: true\n          b: false\nb: text\n"
+		got, err := Unmarshal[string, any]([]byte(data))
+		assert.NoError(t, err, "Unmarshal() error")
+
+		inner, ok := got.Value("a").(*omap.Map[string, any])
+		if assert.True(t, ok, "nested value type") {
+			assert.Equal(t, []string{"z", "x"}, slices.Collect(inner.Keys()), "nested Keys()")
+		}
+
+		out, err := Marshal(got)
+		assert.NoError(t, err, "Marshal() error")
+		assert.Equal(t, data, string(out), "Marshal() output")
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		type config struct {
+			Env Map[string, string] `yaml:"env"`
+		}
+		data := "env:\n    PATH: /bin\n    HOME: /root\n"
+		var c config
+		assert.NoError(t, yaml.Unmarshal([]byte(data), &c), "yaml.Unmarshal() error")
+		assert.Equal(t, []string{"PATH", "HOME"}, c.Env.KeysSlice(), "KeysSlice() after yaml.Unmarshal()")
+
+		out, err := yaml.Marshal(c)
+		assert.NoError(t, err, "yaml.Marshal() error")
+		assert.Equal(t, data, string(out), "yaml.Marshal() output")
+
+		out, err = yaml.Marshal(config{})
+		assert.NoError(t, err, "yaml.Marshal() error")
+		assert.Equal(t, "env: null\n", string(out), "yaml.Marshal() output for nil map")
+	})
+
+	t.Run("null and invalid", func(t *testing.T) {
+		m := Map[string, int]{omap.New(omap.E("x", 1))}
+		err := yaml.Unmarshal([]byte("m: null\n"), &struct{ M *Map[string, int] }{&m})
+		assert.NoError(t, err, "yaml.Unmarshal() error")
+		assert.Equal(t, omap.New(omap.E("x", 1)), m.Map, "yaml.Unmarshal() output for null")
+
+		err = yaml.Unmarshal([]byte("- a\n- b\n"), &m)
+		assert.ErrorContains(t, err, "as mapping", "yaml.Unmarshal() error for sequence")
+
+		_, err = Unmarshal[int, string]([]byte("a: b\n"))
+		assert.ErrorContains(t, err, `parsing key "a" as int`, "Unmarshal() error for invalid key")
+	})
+}
+
+func TestYAMLAliases(t *testing.T) {
+	t.Run("expanded", func(t *testing.T) {
+		data := "a: &x {b: 1}\nc: *x\nd: [*x, *x]\n"
+		got, err := Unmarshal[string, any]([]byte(data))
+		assert.NoError(t, err, "Unmarshal() error")
+		want := omap.New[string, any](omap.E("b", any(1)))
+		assert.Equal(t, want, got.Value("c"), "Value() of alias")
+		assert.Equal(t, []any{want, want}, got.Value("d"), "Value() of aliases in sequence")
+	})
+
+	t.Run("self-referencing", func(t *testing.T) {
+		for _, data := range []string{
+			"a: &x [1, *x]\n",
+			"a: &x {b: 1, c: *x}\n",
+			"a: &x {b: 1, <<: *x}\n",
+		} {
+			_, err := Unmarshal[string, any]([]byte(data))
+			assert.ErrorContains(t, err, "anchor 'x' value contains itself", "Unmarshal(%q) error", data)
+		}
+
+		_, err := Unmarshal[string, *omap.Map[string, any]]([]byte("a: &x {b: *x}\n"))
+		assert.ErrorContains(t, err, "anchor 'x' value contains itself", "Unmarshal() error for nested map")
+	})
+
+	t.Run("excessive", func(t *testing.T) {
+		var b strings.Builder
+		b.WriteString("a: &a [lol, lol, lol, lol, lol, lol, lol, lol, lol, lol]\n")
+		for prev, name := 'a', 'b'; name <= 'f'; prev, name = name, name+1 {
+			aliases := strings.TrimSuffix(strings.Repeat("*"+string(prev)+", ", 10), ", ")
+			fmt.Fprintf(&b, "%c: &%c [%s]\n", name, name, aliases)
+		}
+		b.WriteString("g: [*f, *f, *f, *f, *f, *f, *f, *f, *f, *f]\n")
+
+		start := time.Now()
+		_, err := Unmarshal[string, any]([]byte(b.String()))
+		assert.ErrorContains(t, err, "excessive aliasing", "Unmarshal() error")
+		assert.Less(t, time.Since(start), time.Second, "Unmarshal() duration")
+	})
+}
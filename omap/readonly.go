@@ -0,0 +1,95 @@
+package omap
+
+import (
+	"cmp"
+	"encoding/json"
+	"iter"
+)
+
+var _ json.Marshaler = ReadOnlyMap[string, any]{}
+
+// ReadOnlyMap is a read-only view of an ordered map. It provides the
+// methods of [Map] that read entries, but none that modify them, so it
+// can be returned from an API without a defensive [Map.Clone].
+//
+// The view is not a copy: it reflects changes made to the underlying
+// map after the view was created. The zero value is a view of a nil map.
+type ReadOnlyMap[K cmp.Ordered, V any] struct {
+	m *Map[K, V]
+}
+
+// ReadOnly returns a read-only view of m.
+func (m *Map[K, V]) ReadOnly() ReadOnlyMap[K, V] {
+	return ReadOnlyMap[K, V]{m: m}
+}
+
+// Get returns the value for a key. If the key does not exist,
+// ok will be false and value with be the zero value of its type.
+func (r ReadOnlyMap[K, V]) Get(key K) (value V, ok bool) {
+	return r.m.Get(key)
+}
+
+// Value returns the value for a key. If the key does not exist,
+// value with be the zero value of its type.
+func (r ReadOnlyMap[K, V]) Value(key K) (value V) {
+	return r.m.Value(key)
+}
+
+// Has reports if the key is in the map.
+func (r ReadOnlyMap[K, V]) Has(key K) (ok bool) {
+	return r.m.Has(key)
+}
+
+// Len returns the number of entries in the map.
+func (r ReadOnlyMap[K, V]) Len() int {
+	return r.m.Len()
+}
+
+// At returns the entry at index i in insertion order.
+// If i is out of range, ok will be false.
+func (r ReadOnlyMap[K, V]) At(i int) (entry Entry[K, V], ok bool) {
+	return r.m.At(i)
+}
+
+// IndexOf returns the index of key in insertion order,
+// or -1 if the key is not in the map.
+func (r ReadOnlyMap[K, V]) IndexOf(key K) int {
+	return r.m.IndexOf(key)
+}
+
+// Clone returns a copy of the underlying map, which can be modified
+// without affecting it. If the underlying map is nil, Clone returns nil.
+func (r ReadOnlyMap[K, V]) Clone() *Map[K, V] {
+	return r.m.Clone()
+}
+
+// Entries returns a new slice of the entries in the map in insertion order.
+func (r ReadOnlyMap[K, V]) Entries() []Entry[K, V] {
+	return r.m.Entries()
+}
+
+// All returns an iterator over key-value pairs from the map in insertion order.
+func (r ReadOnlyMap[K, V]) All() iter.Seq2[K, V] {
+	return r.m.All()
+}
+
+// AllBackward returns an iterator over key-value pairs from the map in reverse insertion order.
+func (r ReadOnlyMap[K, V]) AllBackward() iter.Seq2[K, V] {
+	return r.m.AllBackward()
+}
+
+// Keys returns an iterator over keys in the map in insertion order.
+func (r ReadOnlyMap[K, V]) Keys() iter.Seq[K] {
+	return r.m.Keys()
+}
+
+// Values returns an iterator over values in the map in insertion order.
+func (r ReadOnlyMap[K, V]) Values() iter.Seq[V] {
+	return r.m.Values()
+}
+
+// MarshalJSON implements [json.Marshaler], marshaling
+// the underlying map as in [Map.MarshalJSON].
+func (r ReadOnlyMap[K, V]) MarshalJSON() ([]byte, error) {
+	return r.m.MarshalJSON()
+}
@@ -0,0 +1,54 @@
+package omap
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyMap(t *testing.T) {
+	t.Run("live view", func(t *testing.T) {
+		m := New([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...)
+		r := m.ReadOnly()
+		assert.Equal(t, []string{"b", "a"}, slices.Collect(r.Keys()), "Keys()")
+		assert.Equal(t, 1, r.Value("a"), "Value()")
+
+		m.Set("c", 3)
+		m.Delete("b")
+		assert.Equal(t, []string{"a", "c"}, slices.Collect(r.Keys()), "Keys() after modifying map")
+		assert.Equal(t, []int{1, 3}, slices.Collect(r.Values()), "Values() after modifying map")
+		assert.Equal(t, 2, r.Len(), "Len() after modifying map")
+		assert.True(t, r.Has("c"), "Has() after modifying map")
+
+		data, err := json.Marshal(r)
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, `{"a":1,"c":3}`, string(data), "json.Marshal() output")
+
+		cm := r.Clone()
+		cm.Set("d", 4)
+		assert.False(t, r.Has("d"), "Has() after modifying Clone()")
+	})
+
+	t.Run("no mutating methods", func(t *testing.T) {
+		var r any = New[string, int]().ReadOnly()
+		_, ok := r.(interface{ Set(string, int) })
+		assert.False(t, ok, "ReadOnlyMap has Set()")
+		_, ok = r.(interface{ Delete(string) })
+		assert.False(t, ok, "ReadOnlyMap has Delete()")
+		_, ok = r.(interface{ Clear() })
+		assert.False(t, ok, "ReadOnlyMap has Clear()")
+		_, ok = r.(interface{ UnmarshalJSON([]byte) error })
+		assert.False(t, ok, "ReadOnlyMap has UnmarshalJSON()")
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var r ReadOnlyMap[string, int]
+		assert.Equal(t, 0, r.Len(), "Len() on zero value")
+		assert.Empty(t, r.Entries(), "Entries() on zero value")
+	})
+}
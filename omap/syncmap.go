@@ -0,0 +1,154 @@
+package omap
+
+import (
+	"cmp"
+	"iter"
+	"sync"
+)
+
+// SyncMap is an ordered map that is safe for concurrent use
+// by multiple goroutines. The zero value is an empty map ready to use.
+//
+// Iterators returned by SyncMap iterate over a snapshot of the map
+// taken when iteration begins, so the lock is not held while the
+// caller's loop body runs.
+type SyncMap[K cmp.Ordered, V any] struct {
+	mu sync.RWMutex
+	m  Map[K, V]
+}
+
+// NewSyncMap creates a concurrency-safe ordered map from a list of entries.
+func NewSyncMap[K cmp.Ordered, V any](entries ...Entry[K, V]) *SyncMap[K, V] {
+	s := &SyncMap[K, V]{}
+	s.m.Grow(len(entries))
+	for _, el := range entries {
+		s.m.Set(el.Key, el.Value)
+	}
+	return s
+}
+
+// Get returns the value for a key. If the key does not exist,
+// ok will be false and value with be the zero value of its type.
+func (s *SyncMap[K, V]) Get(key K) (value V, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Get(key)
+}
+
+// Value returns the value for a key. If the key does not exist,
+// value with be the zero value of its type.
+func (s *SyncMap[K, V]) Value(key K) (value V) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Value(key)
+}
+
+// Has reports if the key is in the map.
+func (s *SyncMap[K, V]) Has(key K) (ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Has(key)
+}
+
+// Len returns the number of elements in the map.
+func (s *SyncMap[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Len()
+}
+
+// Set sets the value for a key. If the key already exists in the map,
+// its value will be overwritten and its insertion order will be preserved.
+func (s *SyncMap[K, V]) Set(key K, value V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.Set(key, value)
+}
+
+// Delete removes a key from the map.
+func (s *SyncMap[K, V]) Delete(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.Delete(key)
+}
+
+// Pop removes a key from the map and returns its value. If the key does not exist,
+// ok will be false and value with be the zero value of its type.
+func (s *SyncMap[K, V]) Pop(key K) (value V, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.Pop(key)
+}
+
+// Clear removes all entries from the map.
+func (s *SyncMap[K, V]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.Clear()
+}
+
+// Clone returns a copy of the map as a [Map]. This is a shallow clone:
+// the new keys and values are set using ordinary assignment.
+func (s *SyncMap[K, V]) Clone() *Map[K, V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Clone()
+}
+
+// Entries returns a new slice of the entries in the map in insertion order.
+func (s *SyncMap[K, V]) Entries() []Entry[K, V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Entries()
+}
+
+// All returns an iterator over key-value pairs from a snapshot
+// of the map in insertion order.
+func (s *SyncMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(key K, value V) bool) {
+		for _, entry := range s.Entries() {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over keys from a snapshot
+// of the map in insertion order.
+func (s *SyncMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(key K) bool) {
+		for key := range s.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over values from a snapshot
+// of the map in insertion order.
+func (s *SyncMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(value V) bool) {
+		for _, value := range s.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s *SyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.MarshalJSON()
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// Any existing entries in s are replaced by the decoded object.
+func (s *SyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.UnmarshalJSON(data)
+}
@@ -0,0 +1,106 @@
+package omap
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMap(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
+		s := NewSyncMap([]Entry[string, int]{
+			{"b", 2},
+			{"a", 1},
+		}...)
+		s.Set("c", 3)
+		s.Set("b", 20)
+		s.Delete("a")
+		assert.Equal(t, []string{"b", "c"}, slices.Collect(s.Keys()), "Keys()")
+		assert.Equal(t, []int{20, 3}, slices.Collect(s.Values()), "Values()")
+		assert.Equal(t, 2, s.Len(), "Len()")
+		assert.True(t, s.Has("c"), "Has()")
+		assert.Equal(t, 3, s.Value("c"), "Value()")
+
+		value, ok := s.Pop("c")
+		assert.True(t, ok, "Pop() ok")
+		assert.Equal(t, 3, value, "Pop() value")
+
+		data, err := json.Marshal(s)
+		assert.NoError(t, err, "json.Marshal() error")
+		assert.Equal(t, `{"b":20}`, string(data), "json.Marshal() output")
+
+		err = json.Unmarshal([]byte(`{"z":26,"y":25}`), s)
+		assert.NoError(t, err, "json.Unmarshal() error")
+		assert.Equal(t, []Entry[string, int]{{"z", 26}, {"y", 25}}, s.Entries(), "Entries() after json.Unmarshal()")
+
+		s.Clear()
+		assert.Equal(t, 0, s.Len(), "Len() after Clear()")
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var s SyncMap[string, int]
+		s.Set("a", 1)
+		assert.Equal(t, []string{"a"}, slices.Collect(s.Keys()), "Keys()")
+	})
+
+	t.Run("mutating during iteration", func(t *testing.T) {
+		s := NewSyncMap([]Entry[int, int]{
+			{1, 1},
+			{2, 2},
+		}...)
+		for key := range s.Keys() {
+			// Would deadlock if the lock were held during iteration
+			s.Set(key+10, key)
+		}
+		assert.Equal(t, []int{1, 2, 11, 12}, slices.Collect(s.Keys()), "Keys() after mutating during iteration")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		const (
+			writers = 4
+			readers = 4
+			n       = 500
+		)
+		s := &SyncMap[int, int]{}
+		var wg sync.WaitGroup
+		for w := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range n {
+					key := w*n + i
+					s.Set(key, i)
+					if i%2 == 1 {
+						s.Delete(key)
+					}
+				}
+			}()
+		}
+		for range readers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range n {
+					s.Get(i)
+					s.Len()
+					for range s.All() {
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, writers*n/2, s.Len(), "Len() after concurrent writes")
+		m := s.Clone()
+		for w := range writers {
+			// Each writer's remaining keys should be in its insertion order
+			keys := slices.Collect(m.Filter(func(key, _ int) bool {
+				return key/n == w
+			}).Keys())
+			assert.True(t, slices.IsSorted(keys), "writer %d keys in order", w)
+		}
+	})
+}
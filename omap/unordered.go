@@ -0,0 +1,309 @@
+package omap
+
+import (
+	"encoding/json"
+	"iter"
+	"slices"
+)
+
+var (
+	_ json.Marshaler   = (*UnorderedKeyMap[string, any])(nil)
+	_ json.Unmarshaler = (*UnorderedKeyMap[string, any])(nil)
+)
+
+// UnorderedKeyMap is an insertion-ordered map whose keys only need to be
+// comparable, such as small structs or arrays. It provides the core
+// operations of [Map]; operations that rely on ordering keys, like
+// sorting, take a comparison function instead.
+//
+// JSON keys are handled as they are by [Map]: keys that implement
+// [encoding.TextMarshaler] are marshaled as the text they produce, and
+// are parsed with [encoding.TextUnmarshaler] when unmarshaling. A struct
+// key without these methods cannot be marshaled to JSON.
+type UnorderedKeyMap[K comparable, V any] struct {
+	list[K, V]
+}
+
+// NewUnorderedKeyMap creates a new unordered-key map
+// with the entries set in order.
+func NewUnorderedKeyMap[K comparable, V any](entries ...Entry[K, V]) *UnorderedKeyMap[K, V] {
+	m := &UnorderedKeyMap[K, V]{list: newList[K, V](len(entries))}
+	for _, entry := range entries {
+		m.Set(entry.Key, entry.Value)
+	}
+	return m
+}
+
+// IsZero reports if map is empty.
+func (m *UnorderedKeyMap[K, V]) IsZero() bool {
+	return m == nil || len(m.entries) == 0
+}
+
+// Get returns the value for a key. If the key does not exist,
+// ok will be false and value with be the zero value of its type.
+func (m *UnorderedKeyMap[K, V]) Get(key K) (value V, ok bool) {
+	if m == nil || m.entries == nil {
+		var zero V
+		return zero, false
+	}
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Value returns the value for a key. If the key does not exist,
+// value with be the zero value of its type.
+func (m *UnorderedKeyMap[K, V]) Value(key K) (value V) {
+	value, _ = m.Get(key)
+	return value
+}
+
+// Has reports if the key is in the map.
+func (m *UnorderedKeyMap[K, V]) Has(key K) (ok bool) {
+	if m == nil || m.entries == nil {
+		return false
+	}
+	_, ok = m.entries[key]
+	return ok
+}
+
+// Set sets the value for a key. If the key already exists,
+// its value will be overwritten and its position preserved.
+// Set panics if m is nil.
+func (m *UnorderedKeyMap[K, V]) Set(key K, value V) {
+	if m == nil {
+		panic("omap: Set called on nil *UnorderedKeyMap")
+	}
+	m.set(key, value)
+}
+
+// Len returns the number of entries in the map.
+func (m *UnorderedKeyMap[K, V]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.entries)
+}
+
+// Delete removes a key from the map. If the key does not exist, Delete is a no-op.
+func (m *UnorderedKeyMap[K, V]) Delete(key K) {
+	m.Pop(key)
+}
+
+// Pop removes a key from the map and returns its value. If the key does not exist,
+// ok will be false and value with be the zero value of its type.
+func (m *UnorderedKeyMap[K, V]) Pop(key K) (value V, ok bool) {
+	if m == nil {
+		var zero V
+		return zero, false
+	}
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	m.remove(e)
+	return e.value, true
+}
+
+// Clear removes all entries from the map, retaining the allocated capacity
+// so the map can be reused.
+func (m *UnorderedKeyMap[K, V]) Clear() {
+	if m == nil {
+		return
+	}
+	m.clear()
+}
+
+// At returns the entry at index i in insertion order.
+// If i is out of range, ok will be false.
+func (m *UnorderedKeyMap[K, V]) At(i int) (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.at(i)
+	if e == nil {
+		return entry, false
+	}
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// First returns the first entry in insertion order.
+// If the map is empty, ok will be false.
+func (m *UnorderedKeyMap[K, V]) First() (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.first()
+	if e == nil {
+		return entry, false
+	}
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// Last returns the last entry in insertion order.
+// If the map is empty, ok will be false.
+func (m *UnorderedKeyMap[K, V]) Last() (entry Entry[K, V], ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	e := m.last()
+	if e == nil {
+		return entry, false
+	}
+	return Entry[K, V]{Key: e.key, Value: e.value}, true
+}
+
+// IndexOf returns the index of key in insertion order,
+// or -1 if the key is not in the map.
+func (m *UnorderedKeyMap[K, V]) IndexOf(key K) int {
+	if m == nil || m.entries == nil {
+		return -1
+	}
+	e, ok := m.entries[key]
+	if !ok {
+		return -1
+	}
+	return m.indexOf(e)
+}
+
+// SortByKeyFunc sorts the order of m by key using the cmp function,
+// which must follow the semantics of [slices.SortFunc].
+func (m *UnorderedKeyMap[K, V]) SortByKeyFunc(cmp func(a, b K) int) {
+	if m == nil {
+		return
+	}
+	m.compact()
+	slices.SortFunc(m.order, func(a, b *element[K, V]) int {
+		return cmp(a.key, b.key)
+	})
+	m.reindex()
+}
+
+// Clone returns a copy of the map. This is a shallow clone:
+// the new keys and values are set using ordinary assignment.
+func (m *UnorderedKeyMap[K, V]) Clone() *UnorderedKeyMap[K, V] {
+	if m == nil {
+		return nil
+	}
+	cm := &UnorderedKeyMap[K, V]{list: newList[K, V](m.Len())}
+	for key, value := range m.All() {
+		cm.push(key, value)
+	}
+	return cm
+}
+
+// Entries returns a new slice of the entries in m in insertion order.
+// If m is nil, Entries returns nil.
+func (m *UnorderedKeyMap[K, V]) Entries() []Entry[K, V] {
+	if m == nil {
+		return nil
+	}
+	entries := make([]Entry[K, V], 0, m.Len())
+	for key, value := range m.All() {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+	}
+	return entries
+}
+
+// All returns an iterator over key-value pairs from m in insertion order.
+func (m *UnorderedKeyMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(key K, value V) bool) {
+		if m == nil {
+			return
+		}
+		for _, e := range m.order {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// AllBackward returns an iterator over key-value pairs from m in reverse insertion order.
+func (m *UnorderedKeyMap[K, V]) AllBackward() iter.Seq2[K, V] {
+	return func(yield func(key K, value V) bool) {
+		if m == nil {
+			return
+		}
+		for _, e := range slices.Backward(m.order) {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over keys in m in insertion order.
+func (m *UnorderedKeyMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(key K) bool) {
+		if m == nil {
+			return
+		}
+		for _, e := range m.order {
+			if e == nil {
+				continue
+			}
+			if !yield(e.key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over values in m in insertion order.
+func (m *UnorderedKeyMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(value V) bool) {
+		if m == nil {
+			return
+		}
+		for _, e := range m.order {
+			if e == nil {
+				continue
+			}
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements [json.Marshaler].
+// A nil map is marshaled as null, while an empty map is marshaled as {}.
+func (m *UnorderedKeyMap[K, V]) MarshalJSON() ([]byte, error) {
+	return m.MarshalJSONOptions(EncodeOptions{})
+}
+
+// MarshalJSONOptions is like [UnorderedKeyMap.MarshalJSON], but encodes
+// the object as configured by opts.
+func (m *UnorderedKeyMap[K, V]) MarshalJSONOptions(opts EncodeOptions) ([]byte, error) {
+	if m == nil {
+		return []byte(`null`), nil
+	}
+	return m.marshalJSON(opts)
+}
+
+// encodeJSONState implements stateEncoder.
+func (m *UnorderedKeyMap[K, V]) encodeJSONState(w jsonWriter, st *encodeState) error {
+	if m == nil {
+		w.Write([]byte(`null`))
+		return nil
+	}
+	return m.encodeJSON(w, st)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// Any existing entries in m are replaced by the decoded object.
+// Decoding null leaves m unchanged.
+func (m *UnorderedKeyMap[K, V]) UnmarshalJSON(data []byte) error {
+	return m.unmarshalJSON(data, DecodeOptions{})
+}
@@ -0,0 +1,105 @@
+package omap
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type point struct {
+	X, Y int
+}
+
+// pointKey is a point that marshals to text as "x,y".
+type pointKey point
+
+func (p pointKey) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%d,%d", p.X, p.Y), nil
+}
+
+func (p *pointKey) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d,%d", &p.X, &p.Y)
+	return err
+}
+
+func TestUnorderedKeyMap(t *testing.T) {
+	t.Run("struct keys", func(t *testing.T) {
+		m := NewUnorderedKeyMap([]Entry[point, string]{
+			{point{1, 2}, "a"},
+			{point{0, 0}, "b"},
+			{point{2, 1}, "c"},
+		}...)
+		m.Set(point{1, 2}, "d")
+		m.Set(point{3, 3}, "e")
+		assert.Equal(t, []point{{1, 2}, {0, 0}, {2, 1}, {3, 3}}, slices.Collect(m.Keys()), "Keys() after Set()")
+		assert.Equal(t, []string{"d", "b", "c", "e"}, slices.Collect(m.Values()), "Values() after Set()")
+		assert.Equal(t, "b", m.Value(point{0, 0}), "Value()")
+		assert.Equal(t, 2, m.IndexOf(point{2, 1}), "IndexOf()")
+
+		m.Delete(point{0, 0})
+		assert.False(t, m.Has(point{0, 0}), "Has() after Delete()")
+		assert.Equal(t, 3, m.Len(), "Len() after Delete()")
+		entry, ok := m.At(1)
+		assert.True(t, ok, "At() ok")
+		assert.Equal(t, Entry[point, string]{point{2, 1}, "c"}, entry, "At() after Delete()")
+	})
+
+	t.Run("sort by key func", func(t *testing.T) {
+		m := NewUnorderedKeyMap([]Entry[point, int]{
+			{point{2, 0}, 1},
+			{point{1, 5}, 2},
+			{point{1, 3}, 3},
+		}...)
+		m.SortByKeyFunc(func(a, b point) int {
+			return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+		})
+		assert.Equal(t, []point{{1, 3}, {1, 5}, {2, 0}}, slices.Collect(m.Keys()), "Keys() after SortByKeyFunc()")
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		var m *UnorderedKeyMap[point, int]
+		assert.Equal(t, 0, m.Len(), "Len() on nil map")
+		assert.False(t, m.Has(point{}), "Has() on nil map")
+		assert.Nil(t, m.Clone(), "Clone() on nil map")
+		assert.PanicsWithValue(t, "omap: Set called on nil *UnorderedKeyMap", func() {
+			m.Set(point{}, 1)
+		}, "Set() on nil map")
+	})
+}
+
+func TestUnorderedKeyMapJSON(t *testing.T) {
+	t.Run("text marshaler keys", func(t *testing.T) {
+		m := NewUnorderedKeyMap([]Entry[pointKey, int]{
+			{pointKey{3, 4}, 1},
+			{pointKey{1, 2}, 2},
+		}...)
+		data, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal()")
+		assert.Equal(t, `{"3,4":1,"1,2":2}`, string(data), "json.Marshal()")
+
+		got := NewUnorderedKeyMap[pointKey, int]()
+		assert.NoError(t, json.Unmarshal(data, got), "json.Unmarshal()")
+		assert.Equal(t, m.Entries(), got.Entries(), "Entries() after json.Unmarshal()")
+	})
+
+	t.Run("ordered keys", func(t *testing.T) {
+		m := NewUnorderedKeyMap([]Entry[int, string]{
+			{2, "b"},
+			{1, "a"},
+		}...)
+		data, err := json.Marshal(m)
+		assert.NoError(t, err, "json.Marshal()")
+		assert.Equal(t, `{"2":"b","1":"a"}`, string(data), "json.Marshal()")
+	})
+
+	t.Run("unsupported keys", func(t *testing.T) {
+		m := NewUnorderedKeyMap(Entry[point, int]{point{1, 2}, 1})
+		_, err := json.Marshal(m)
+		assert.Error(t, err, "json.Marshal() with struct keys")
+		assert.Error(t, json.Unmarshal([]byte(`{"1,2":1}`), m), "json.Unmarshal() with struct keys")
+	})
+}